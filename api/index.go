@@ -1,34 +1,101 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"os"
+	"sync"
 
 	"golang-template/api/middleware"
 	"golang-template/api/route"
+	"golang-template/app/core/module"
 	"golang-template/configs"
+	"golang-template/infrastructure/firebase"
 	"golang-template/infrastructure/logger"
+	"golang-template/infrastructure/tracing"
+	"golang-template/pkg/common/errors"
+	"golang-template/pkg/common/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	handlerRouter *gin.Engine
+	handlerErr    error
+	handlerOnce   sync.Once
+)
+
 // Handler is the entry point for DEPLOYMENT
 func Handler(w http.ResponseWriter, r *http.Request) {
-	cfg := configs.LoadConfig()
-	log := logger.NewLogger(cfg.Debug)
-	router := SetupRouter(cfg, log)
-	router.ServeHTTP(w, r)
+	// Modules are initialized once per function instance
+	handlerOnce.Do(func() {
+		cfg := configs.LoadConfig()
+		var log logger.Logger
+		if log, handlerErr = logger.NewLoggerFromConfigE(cfg); handlerErr != nil {
+			fmt.Fprintln(os.Stderr, "Failed to create logger:", handlerErr)
+			return
+		}
+		logger.SetDefault(log)
+
+		// Spans are flushed by the batcher while the instance is warm
+		if _, err := tracing.Setup(context.Background(), cfg, log); err != nil {
+			log.Error("Failed to setup tracing", "error", err)
+		}
+
+		fbClient, _ := firebase.Initialize(cfg, log)
+
+		modules := module.Default()
+		if handlerErr = modules.Init(module.Dependencies{
+			Config:    cfg,
+			Logger:    log,
+			Firebase:  fbClient,
+			AdminAuth: middleware.AdminAuth(cfg),
+		}); handlerErr != nil {
+			log.Error("Failed to initialize modules", "error", handlerErr)
+			return
+		}
+
+		handlerRouter, handlerErr = SetupRouter(cfg, log, modules)
+		if handlerErr != nil {
+			log.Error("Failed to setup router", "error", handlerErr)
+		}
+	})
+
+	if handlerErr != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	handlerRouter.ServeHTTP(w, r)
 }
 
-func SetupRouter(cfg *configs.Config, log logger.Logger) *gin.Engine {
+func SetupRouter(cfg *configs.Config, log logger.Logger, modules *module.Registry) (*gin.Engine, error) {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
 
-	middleware.Setup(router, cfg, log)
+	if err := response.Configure(response.Options{
+		ErrorFormat:        cfg.ErrorFormat,
+		ProblemTypeBaseURL: cfg.ErrorTypeBaseURL,
+		StreamWriteTimeout: cfg.ServerWriteTimeout,
+		Logger: func(ctx context.Context) response.Logger {
+			return logger.FromContext(ctx)
+		},
+	}); err != nil {
+		return nil, err
+	}
+	errors.SetCaptureStack(cfg.ErrorCaptureStack)
+
+	if err := middleware.Setup(router, cfg, log); err != nil {
+		return nil, err
+	}
 
-	route.RegisterRoutes(router, cfg, log)
+	if err := route.RegisterRoutes(router, cfg, log, modules); err != nil {
+		return nil, err
+	}
 
-	return router
+	return router, nil
 }
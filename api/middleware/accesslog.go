@@ -0,0 +1,342 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-template/configs"
+	"golang-template/infrastructure/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Fields accepted by LOG_ACCESS_FIELDS
+const (
+	AccessFieldMethod    = "method"
+	AccessFieldPath      = "path"
+	AccessFieldRoute     = "route"
+	AccessFieldQuery     = "query"
+	AccessFieldStatus    = "status"
+	AccessFieldLatency   = "latency"
+	AccessFieldIP        = "ip"
+	AccessFieldUserAgent = "user_agent"
+	AccessFieldReferer   = "referer"
+	AccessFieldBytesIn   = "bytes_in"
+	AccessFieldBytesOut  = "bytes_out"
+	AccessFieldPrincipal = "uid"
+)
+
+var accessFields = map[string]bool{
+	AccessFieldMethod: true, AccessFieldPath: true, AccessFieldRoute: true,
+	AccessFieldQuery: true, AccessFieldStatus: true, AccessFieldLatency: true,
+	AccessFieldIP: true, AccessFieldUserAgent: true, AccessFieldReferer: true,
+	AccessFieldBytesIn: true, AccessFieldBytesOut: true, AccessFieldPrincipal: true,
+}
+
+// accessLog writes one entry per request to the "access" logger
+type accessLog struct {
+	log       *zap.Logger
+	fields    map[string]bool
+	headers   []string
+	skipPaths []string
+	slow      time.Duration
+	levels    statusLevels
+	gcp       bool
+}
+
+// AccessLog returns the access log middleware configured by the LOG_ACCESS_*
+// settings
+func AccessLog(cfg *configs.Config, log logger.Logger) (gin.HandlerFunc, error) {
+	fields := make(map[string]bool, len(cfg.LogAccessFields))
+	for _, name := range cfg.LogAccessFields {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !accessFields[name] {
+			return nil, fmt.Errorf("unknown access log field %q", name)
+		}
+		fields[name] = true
+	}
+
+	levels, err := parseStatusLevels(cfg.LogAccessLevels)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &accessLog{
+		// The caller would always be this middleware
+		log:     log.Named("access").ZapLogger().WithOptions(zap.WithCaller(false)),
+		fields:  fields,
+		headers: headersWithoutFields(cfg.LogAccessHeaders, fields),
+		slow:    cfg.LogAccessSlowThreshold,
+		levels:  levels,
+		gcp:     cfg.LogFormat == logger.FormatGCP,
+	}
+
+	for _, path := range cfg.LogAccessSkipPaths {
+		if path = strings.TrimSpace(path); path != "" {
+			a.skipPaths = append(a.skipPaths, path)
+		}
+	}
+
+	return a.handle, nil
+}
+
+// fieldHeaders are the headers already logged by a field
+var fieldHeaders = map[string]string{
+	"User-Agent": AccessFieldUserAgent,
+	"Referer":    AccessFieldReferer,
+}
+
+// headersWithoutFields drops the headers whose field is enabled, so they
+// are not logged twice
+func headersWithoutFields(headers []string, fields map[string]bool) []string {
+	kept := make([]string, 0, len(headers))
+	for _, name := range headers {
+		if fields[fieldHeaders[http.CanonicalHeaderKey(strings.TrimSpace(name))]] {
+			continue
+		}
+		kept = append(kept, name)
+	}
+	return kept
+}
+
+func (a *accessLog) handle(c *gin.Context) {
+	start := time.Now()
+
+	body := &countingReader{ReadCloser: c.Request.Body}
+	if c.Request.Body != nil {
+		c.Request.Body = body
+	}
+
+	c.Next()
+
+	latency := time.Since(start)
+	status := c.Writer.Status()
+	level := a.levels.level(status)
+
+	slow := a.slow > 0 && latency >= a.slow
+	if slow && level < zapcore.WarnLevel {
+		level = zapcore.WarnLevel
+	}
+
+	// Skipped paths are still logged when something went wrong
+	if level < zapcore.WarnLevel && a.skipped(c) {
+		return
+	}
+
+	msg := "Request completed"
+	if slow {
+		msg = "Slow request"
+	}
+
+	entry := a.log.Check(level, msg)
+	if entry == nil {
+		return
+	}
+
+	fields := []zapcore.Field{zap.String("request_id", c.GetString(RequestIDKey))}
+	fields = append(fields, a.requestFields(c, status, latency, body.n)...)
+
+	if traceID, spanID := traceFromRequest(c.Request); traceID != "" {
+		fields = append(fields, zap.String(logger.TraceIDKey, traceID))
+		if spanID != "" {
+			fields = append(fields, zap.String(logger.SpanIDKey, spanID))
+		}
+	}
+
+	if headers := a.requestHeaders(c); len(headers) > 0 {
+		fields = append(fields, zap.Any("headers", headers))
+	}
+
+	if len(c.Errors) > 0 {
+		fields = append(fields, zap.Strings("errors", c.Errors.Errors()))
+	}
+
+	// Slow requests carry what is needed to reproduce them
+	if slow {
+		fields = append(fields,
+			zap.Duration("threshold", a.slow),
+			zap.String("handler", c.HandlerName()),
+			zap.String("query", c.Request.URL.RawQuery),
+			zap.Int64("content_length", c.Request.ContentLength),
+		)
+	}
+
+	if a.gcp {
+		fields = append(fields, zap.Object("httpRequest", logger.HTTPRequest{
+			RequestMethod: c.Request.Method,
+			RequestURL:    c.Request.URL.String(),
+			RequestSize:   body.n,
+			Status:        status,
+			ResponseSize:  int64(bytesOut(c)),
+			UserAgent:     c.Request.UserAgent(),
+			RemoteIP:      c.ClientIP(),
+			Referer:       c.Request.Referer(),
+			Latency:       latency,
+			Protocol:      c.Request.Proto,
+		}))
+	}
+
+	entry.Write(fields...)
+}
+
+// requestFields returns the configured LOG_ACCESS_FIELDS
+func (a *accessLog) requestFields(c *gin.Context, status int, latency time.Duration, bytesIn int64) []zapcore.Field {
+	fields := make([]zapcore.Field, 0, len(a.fields))
+
+	if a.fields[AccessFieldMethod] {
+		fields = append(fields, zap.String("method", c.Request.Method))
+	}
+	if a.fields[AccessFieldPath] {
+		fields = append(fields, zap.String("path", c.Request.URL.Path))
+	}
+	if a.fields[AccessFieldRoute] {
+		fields = append(fields, zap.String("route", c.FullPath()))
+	}
+	if a.fields[AccessFieldQuery] && c.Request.URL.RawQuery != "" {
+		fields = append(fields, zap.String("query", c.Request.URL.RawQuery))
+	}
+	if a.fields[AccessFieldStatus] {
+		fields = append(fields, zap.Int("status", status))
+	}
+	if a.fields[AccessFieldLatency] {
+		fields = append(fields, zap.Duration("latency", latency))
+	}
+	if a.fields[AccessFieldIP] {
+		fields = append(fields, zap.String("ip", c.ClientIP()))
+	}
+	if a.fields[AccessFieldUserAgent] {
+		fields = append(fields, zap.String("user_agent", c.Request.UserAgent()))
+	}
+	if a.fields[AccessFieldReferer] && c.Request.Referer() != "" {
+		fields = append(fields, zap.String("referer", c.Request.Referer()))
+	}
+	if a.fields[AccessFieldBytesIn] {
+		fields = append(fields, zap.Int64("bytes_in", bytesIn))
+	}
+	if a.fields[AccessFieldBytesOut] {
+		fields = append(fields, zap.Int("bytes_out", bytesOut(c)))
+	}
+	if a.fields[AccessFieldPrincipal] {
+		if uid := c.GetString(PrincipalKey); uid != "" {
+			fields = append(fields, zap.String("uid", uid))
+		}
+	}
+
+	return fields
+}
+
+// requestHeaders returns only the allow-listed request headers, values
+// still go through the logger redaction
+func (a *accessLog) requestHeaders(c *gin.Context) map[string]string {
+	headers := make(map[string]string, len(a.headers))
+	for _, name := range a.headers {
+		if value := c.Request.Header.Get(name); value != "" {
+			headers[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+	return headers
+}
+
+// skipped reports whether the request path or route matches a skip entry.
+// Entries ending with '*' match as a prefix.
+func (a *accessLog) skipped(c *gin.Context) bool {
+	path := c.Request.URL.Path
+	route := c.FullPath()
+
+	for _, skip := range a.skipPaths {
+		if prefix, ok := strings.CutSuffix(skip, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+			continue
+		}
+		if path == skip || route == skip {
+			return true
+		}
+	}
+	return false
+}
+
+func bytesOut(c *gin.Context) int {
+	if size := c.Writer.Size(); size > 0 {
+		return size
+	}
+	return 0
+}
+
+// statusLevels maps response statuses to log levels, exact codes take
+// precedence over classes
+type statusLevels struct {
+	codes   map[int]zapcore.Level
+	classes map[int]zapcore.Level
+}
+
+// parseStatusLevels parses entries such as "5xx=error,4xx=warn,404=info"
+func parseStatusLevels(entries []string) (statusLevels, error) {
+	levels := statusLevels{
+		codes:   make(map[int]zapcore.Level),
+		classes: make(map[int]zapcore.Level),
+	}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return levels, fmt.Errorf("invalid access log level %q", entry)
+		}
+
+		level, err := logger.ParseLevel(value)
+		if err != nil {
+			return levels, fmt.Errorf("access log level %q: %w", entry, err)
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		if class, ok := strings.CutSuffix(key, "xx"); ok && len(class) == 1 && class[0] >= '1' && class[0] <= '5' {
+			levels.classes[int(class[0]-'0')] = level
+			continue
+		}
+
+		code, err := strconv.Atoi(key)
+		if err != nil || code < 100 || code > 599 {
+			return levels, fmt.Errorf("invalid access log status %q", key)
+		}
+		levels.codes[code] = level
+	}
+
+	return levels, nil
+}
+
+func (s statusLevels) level(status int) zapcore.Level {
+	if level, ok := s.codes[status]; ok {
+		return level
+	}
+	if level, ok := s.classes[status/100]; ok {
+		return level
+	}
+	return zapcore.InfoLevel
+}
+
+// countingReader counts the request body bytes read by the handlers
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHeadersWithoutFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		fields  map[string]bool
+		want    []string
+	}{
+		{
+			name:    "field enabled",
+			headers: []string{"user-agent", "Referer", "Content-Type"},
+			fields:  map[string]bool{AccessFieldUserAgent: true},
+			want:    []string{"Referer", "Content-Type"},
+		},
+		{
+			name:    "fields disabled",
+			headers: []string{"User-Agent", "Referer"},
+			fields:  map[string]bool{AccessFieldMethod: true},
+			want:    []string{"User-Agent", "Referer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headersWithoutFields(tt.headers, tt.fields); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("headersWithoutFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStatusLevels(t *testing.T) {
+	levels, err := parseStatusLevels([]string{"4xx=warn", "404=info", " 5xx=error ", ""})
+	if err != nil {
+		t.Fatalf("parseStatusLevels() error = %v", err)
+	}
+
+	tests := map[int]zapcore.Level{
+		200: zapcore.InfoLevel,
+		404: zapcore.InfoLevel,
+		409: zapcore.WarnLevel,
+		503: zapcore.ErrorLevel,
+	}
+	for status, want := range tests {
+		if got := levels.level(status); got != want {
+			t.Errorf("level(%d) = %v, want %v", status, got, want)
+		}
+	}
+
+	for _, invalid := range []string{"4xx", "4xx=", "6xx=warn", "99=info", "404=verbose"} {
+		if _, err := parseStatusLevels([]string{invalid}); err == nil {
+			t.Errorf("parseStatusLevels(%q) error = nil, want an error", invalid)
+		}
+	}
+}
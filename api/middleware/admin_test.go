@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang-template/configs"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetricsAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		want          int
+	}{
+		{"valid", "scrape", "Bearer scrape", http.StatusOK},
+		{"missing", "scrape", "", http.StatusUnauthorized},
+		{"wrong", "scrape", "Bearer admin", http.StatusUnauthorized},
+		{"scheme", "scrape", "Basic scrape", http.StatusUnauthorized},
+		{"unset token", "", "Bearer ", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/metrics", MetricsAuth(&configs.Config{MetricsToken: tt.token}), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
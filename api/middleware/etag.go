@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"golang-template/pkg/common/conditional"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalRequests adds ETags to successful GET responses and answers
+// 304 Not Modified to If-None-Match and If-Modified-Since. The ETag set by
+// the handler, e.g. by response.Success for entities, is used as is,
+// otherwise it is a hash of the body, weak when weak is set, which only
+// serves caching. Bodies are buffered up to maxBody bytes, larger ones are
+// sent as they are written without a body hash. For PUT, PATCH and DELETE
+// the If-Match and If-Unmodified-Since headers are stored in the request
+// context for the entity of the :id route parameter and checked by the
+// repositories.
+func ConditionalRequests(weak bool, maxBody int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPut, http.MethodPatch, http.MethodDelete:
+			if p := conditional.FromRequest(c.Request, c.Param("id")); !p.Empty() {
+				c.Request = c.Request.WithContext(conditional.NewContext(c.Request.Context(), p))
+			}
+			c.Next()
+			return
+		default:
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, max: maxBody}
+		c.Writer = w
+		defer func() {
+			c.Writer = w.ResponseWriter
+		}()
+
+		c.Next()
+
+		// Streamed and large responses were already sent
+		if w.passthrough {
+			return
+		}
+
+		header := w.Header()
+		if w.Status() == http.StatusOK {
+			etag := header.Get("ETag")
+			if etag == "" {
+				etag = conditional.StrongTag(w.body.Bytes())
+				if weak {
+					etag = conditional.WeakTag(w.body.Bytes())
+				}
+				header.Set("ETag", etag)
+			}
+
+			if notModified(c.Request, etag, header.Get("Last-Modified")) {
+				// A 304 carries the validators but no representation headers
+				header.Del("Content-Type")
+				header.Del("Content-Length")
+				w.ResponseWriter.WriteHeader(http.StatusNotModified)
+				w.ResponseWriter.WriteHeaderNow()
+				return
+			}
+		}
+
+		w.ResponseWriter.WriteHeader(w.Status())
+		if c.Request.Method == http.MethodHead {
+			w.ResponseWriter.WriteHeaderNow()
+			return
+		}
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when the
+// request has no If-None-Match
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if tags := conditional.ParseTags(r.Header.Get("If-None-Match")); len(tags) > 0 {
+		return !conditional.NoneMatch(tags, etag)
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || lastModified == "" {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	return err == nil && !modified.Truncate(time.Second).After(since)
+}
+
+// bufferedWriter holds the body until the handler returns so the ETag can
+// be computed, it switches to pass through when the handler flushes or the
+// body grows beyond max bytes
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body        bytes.Buffer
+	max         int
+	passthrough bool
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	if !w.passthrough && w.body.Len()+len(data) > w.max {
+		w.release()
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.passthrough && w.ResponseWriter.Written() || w.body.Len() > 0
+}
+
+func (w *bufferedWriter) Size() int {
+	if w.passthrough {
+		return w.ResponseWriter.Size()
+	}
+	return w.body.Len()
+}
+
+// WriteHeaderNow is deferred until the handler returns
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Flush sends the buffered body and writes everything else directly
+func (w *bufferedWriter) Flush() {
+	w.release()
+	w.ResponseWriter.Flush()
+}
+
+// release sends the buffered body and switches to pass through
+func (w *bufferedWriter) release() {
+	if w.passthrough {
+		return
+	}
+	w.passthrough = true
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+}
+
+// Unwrap exposes the connection to http.ResponseController
+func (w *bufferedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-template/pkg/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type etagEntity struct {
+	ID        string    `json:"id"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Version   int64     `json:"version"`
+}
+
+func (e *etagEntity) GetUpdatedAt() time.Time { return e.UpdatedAt }
+func (e *etagEntity) GetVersion() int64       { return e.Version }
+
+func newETagRouter(maxBody int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ConditionalRequests(false, maxBody))
+	router.GET("/entity", func(c *gin.Context) {
+		response.OK(c, &etagEntity{ID: "a", UpdatedAt: time.Unix(1700000000, 0), Version: 3})
+	})
+	router.GET("/text", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("x", 64))
+	})
+	return router
+}
+
+func serve(router *gin.Engine, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConditionalRequestsEntity(t *testing.T) {
+	router := newETagRouter(1 << 20)
+
+	rec := serve(router, "/entity", nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"v3"` {
+		t.Fatalf("GET = %d with ETag %q, want 200 with \"v3\"", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	rec = serve(router, "/entity", http.Header{"If-None-Match": {`"v3"`}})
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("If-None-Match = %d with %d bytes, want 304 without body", rec.Code, rec.Body.Len())
+	}
+
+	rec = serve(router, "/entity", http.Header{"Accept": {"text/csv"}})
+	if rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("Accept text/csv = %d, want 406", rec.Code)
+	}
+	if etag, lastModified := rec.Header().Get("ETag"), rec.Header().Get("Last-Modified"); etag != "" || lastModified != "" {
+		t.Errorf("406 carries ETag %q and Last-Modified %q", etag, lastModified)
+	}
+}
+
+func TestConditionalRequestsBodyHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBody  int
+		wantETag bool
+	}{
+		{name: "buffered", maxBody: 64, wantETag: true},
+		{name: "over the limit", maxBody: 63, wantETag: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(newETagRouter(tt.maxBody), "/text", nil)
+			if rec.Code != http.StatusOK || rec.Body.Len() != 64 {
+				t.Fatalf("GET = %d with %d bytes, want 200 with 64", rec.Code, rec.Body.Len())
+			}
+			if got := rec.Header().Get("ETag") != ""; got != tt.wantETag {
+				t.Errorf("ETag set = %v, want %v", got, tt.wantETag)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"golang-template/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records the request count, latency and in-flight gauges labelled
+// by method, route template and status class
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		done := metrics.RequestStarted(c.Request.Method, c.FullPath())
+		defer func() {
+			done(c.Writer.Status())
+		}()
+
+		c.Next()
+	}
+}
@@ -1,25 +1,58 @@
 package middleware
 
 import (
+	"fmt"
+	"net/http"
 	"os"
-	"time"
+	"strconv"
+	"strings"
 
 	"golang-template/configs"
 	"golang-template/infrastructure/logger"
+	"golang-template/infrastructure/metrics"
+	"golang-template/pkg/common/principal"
+	"golang-template/pkg/common/requestid"
 	"golang-template/pkg/common/response"
 
 	"github.com/gin-contrib/cors"
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/ulule/limiter/v3"
 	mgin "github.com/ulule/limiter/v3/drivers/middleware/gin"
 	"github.com/ulule/limiter/v3/drivers/store/memory"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func Setup(router *gin.Engine, cfg *configs.Config, log logger.Logger) {
-	// logger middleware
-	router.Use(ginzap.Ginzap(log.ZapLogger(), time.RFC3339, true))
+// Keys stored on the gin context
+const (
+	RequestIDKey = "RequestID"
+	PrincipalKey = "uid"
+)
+
+func Setup(router *gin.Engine, cfg *configs.Config, log logger.Logger) error {
+	// tracing middleware, first so the server span covers the whole request
+	if cfg.TracingEnabled {
+		router.Use(Tracing())
+	}
+
+	// request ID middleware, so every response carries the ID
+	router.Use(requestIDMiddleware())
+
+	// metrics middleware, before the rate limiter so rejections are counted
+	if cfg.MetricsEnabled {
+		router.Use(Metrics())
+	}
+
+	// request scoped logger middleware
+	router.Use(contextLoggerMiddleware(log))
+
+	// access log middleware, the "access" logger level can be changed at runtime
+	accessLog, err := AccessLog(cfg, log)
+	if err != nil {
+		return err
+	}
+	router.Use(accessLog)
 	router.Use(ginzap.RecoveryWithZap(log.ZapLogger(), true))
 
 	// CORS middleware
@@ -31,8 +64,12 @@ func Setup(router *gin.Engine, cfg *configs.Config, log logger.Logger) {
 	// security headers
 	router.Use(securityHeadersMiddleware())
 
-	// request ID middleware
-	router.Use(requestIDMiddleware())
+	// ETags and conditional requests, last so only handler output is buffered
+	if cfg.ETagEnabled {
+		router.Use(ConditionalRequests(cfg.ETagWeak, cfg.ETagMaxBodySize))
+	}
+
+	return nil
 }
 
 // corsMiddleware configures CORS
@@ -68,6 +105,7 @@ func rateLimiterMiddleware(router *gin.Engine, cfg *configs.Config) {
 		//  context was aborted by the rate limiter
 		if c.Writer.Status() == 429 {
 			c.Abort()
+			metrics.RateLimited(c.Request.Method, c.FullPath())
 			response.RateLimitExceeded(c)
 			return
 		}
@@ -103,15 +141,92 @@ func securityHeadersMiddleware() gin.HandlerFunc {
 	}
 }
 
-// requestIDMiddleware  request ID to each request
+// requestIDMiddleware  request ID to each request. Client supplied IDs are
+// reused only when they pass requestid.Valid, otherwise a new one is generated.
 func requestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := c.Request.Header.Get("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()
+		requestID := c.Request.Header.Get(requestid.Header)
+		if !requestid.Valid(requestID) {
+			requestID = requestid.New()
 		}
-		c.Set("RequestID", requestID)
-		c.Header("X-Request-ID", requestID)
+		c.Set(RequestIDKey, requestID)
+		c.Header(requestid.Header, requestID)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), requestID))
 		c.Next()
 	}
 }
+
+// contextLoggerMiddleware installs a logger carrying the request correlation
+// fields into the request context, retrieve it with logger.FromContext
+func contextLoggerMiddleware(log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := []interface{}{
+			"request_id", c.GetString(RequestIDKey),
+		}
+
+		traceID, spanID := traceFromRequest(c.Request)
+		if traceID != "" {
+			fields = append(fields, logger.TraceIDKey, traceID)
+		}
+		if spanID != "" {
+			fields = append(fields, logger.SpanIDKey, spanID)
+		}
+
+		if uid := c.GetString(PrincipalKey); uid != "" {
+			fields = append(fields, "uid", uid)
+		}
+
+		if route := c.FullPath(); route != "" {
+			fields = append(fields, "route", route)
+		}
+
+		ctx := logger.WithContext(c.Request.Context(), log.With(fields...))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// SetPrincipal records the authenticated user ID on the request and adds it
+// to the request scoped logger and context, see principal.FromContext.
+// Authentication middleware should call it once the caller is verified.
+func SetPrincipal(c *gin.Context, uid string) {
+	c.Set(PrincipalKey, uid)
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(semconv.EnduserID(uid))
+	ctx := logger.WithFields(c.Request.Context(), "uid", uid)
+	c.Request = c.Request.WithContext(principal.NewContext(ctx, uid))
+}
+
+// traceFromRequest returns the IDs of the server span when tracing is
+// enabled, otherwise the ones sent by the caller
+func traceFromRequest(r *http.Request) (traceID, spanID string) {
+	if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+		return sc.TraceID().String(), sc.SpanID().String()
+	}
+	return traceFromHeaders(r)
+}
+
+// traceFromHeaders extracts the trace and span IDs from a W3C traceparent
+// or a Google Cloud X-Cloud-Trace-Context header. Span IDs are returned as
+// 16 hex characters in both cases.
+func traceFromHeaders(r *http.Request) (traceID, spanID string) {
+	// traceparent: version-traceid-spanid-flags
+	if parts := strings.Split(r.Header.Get("traceparent"), "-"); len(parts) == 4 && len(parts[1]) == 32 {
+		if len(parts[2]) == 16 {
+			spanID = parts[2]
+		}
+		return parts[1], spanID
+	}
+
+	// X-Cloud-Trace-Context: TRACE_ID/SPAN_ID;o=OPTIONS, SPAN_ID is decimal
+	if header := r.Header.Get("X-Cloud-Trace-Context"); header != "" {
+		traceID, rest, _ := strings.Cut(header, "/")
+		span, _, _ := strings.Cut(rest, ";")
+		if id, err := strconv.ParseUint(span, 10, 64); err == nil && id != 0 {
+			spanID = fmt.Sprintf("%016x", id)
+		}
+		return traceID, spanID
+	}
+
+	return "", ""
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+
+	"golang-template/infrastructure/metrics"
+	"golang-template/infrastructure/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span per request, continuing the trace from the
+// W3C traceparent header. The span is stored in the request context so
+// services and the Firestore client create child spans.
+func Tracing() gin.HandlerFunc {
+	tracer := tracing.Tracer()
+
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := metrics.Route(c.FullPath())
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(metrics.Method(c.Request.Method)),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.URLScheme(scheme(c.Request)),
+				semconv.ServerAddress(c.Request.Host),
+				semconv.ClientAddress(c.ClientIP()),
+				semconv.UserAgentOriginal(c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if uid := c.GetString(PrincipalKey); uid != "" {
+			span.SetAttributes(semconv.EnduserID(uid))
+		}
+		if requestID := c.GetString(RequestIDKey); requestID != "" {
+			span.SetAttributes(attribute.String("http.request.id", requestID))
+		}
+
+		// Client errors are not span errors for server spans
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		for _, err := range c.Errors {
+			span.RecordError(err.Err)
+		}
+	}
+}
+
+func scheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
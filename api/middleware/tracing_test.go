@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func serveTraced(t *testing.T, path, traceparent string, status int) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(PrincipalKey, "uid-1")
+		c.Next()
+	})
+	router.Use(Tracing())
+	router.GET("/users/:id", func(c *gin.Context) {
+		if !trace.SpanFromContext(c.Request.Context()).SpanContext().IsValid() {
+			t.Error("handler context carries no span")
+		}
+		c.Status(status)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if traceparent != "" {
+		req.Header.Set("traceparent", traceparent)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	span := serveTraced(t, "/users/42", "00-"+traceID+"-00f067aa0ba902b7-01", http.StatusNotFound)
+
+	if span.Name() != "GET /users/:id" {
+		t.Errorf("Name() = %q, want the route template", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("SpanKind() = %v, want server", span.SpanKind())
+	}
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("TraceID() = %s, want the incoming %s", got, traceID)
+	}
+	if got := spanAttribute(span, "http.response.status_code").AsInt64(); got != http.StatusNotFound {
+		t.Errorf("status attribute = %d, want 404", got)
+	}
+	if got := spanAttribute(span, "enduser.id").AsString(); got != "uid-1" {
+		t.Errorf("enduser.id = %q, want uid-1", got)
+	}
+	// Client errors are not span errors
+	if span.Status().Code != codes.Unset {
+		t.Errorf("Status() = %v, want unset", span.Status())
+	}
+}
+
+func TestTracingServerError(t *testing.T) {
+	span := serveTraced(t, "/users/42", "", http.StatusServiceUnavailable)
+
+	if span.Status().Code != codes.Error {
+		t.Errorf("Status() = %v, want error", span.Status())
+	}
+	if span.Parent().IsValid() {
+		t.Error("span without traceparent has a parent")
+	}
+}
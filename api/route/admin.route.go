@@ -0,0 +1,85 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"time"
+
+	"golang-template/api/middleware"
+	"golang-template/app/module/admin/handler"
+	"golang-template/configs"
+	"golang-template/infrastructure/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAdminRoutes mounts the operational endpoints under /admin. They
+// are disabled unless ADMIN_ENABLED is set and require ADMIN_TOKEN.
+func RegisterAdminRoutes(router *gin.Engine, cfg *configs.Config, log logger.Logger) error {
+	if !cfg.AdminEnabled {
+		return nil
+	}
+
+	if cfg.AdminToken == "" {
+		return errors.New("ADMIN_ENABLED requires ADMIN_TOKEN")
+	}
+
+	log.Info("Enabling admin endpoints")
+
+	adminGroup := router.Group("/admin", middleware.AdminAuth(cfg))
+
+	logLevelHandler := handler.NewLogLevelHandler(log.Levels())
+	adminGroup.GET("/log-level", logLevelHandler.Get)
+	adminGroup.PUT("/log-level", logLevelHandler.Update)
+
+	// Runtime diagnostics
+	diagnosticsHandler := handler.NewDiagnosticsHandler(cfg)
+	debugGroup := adminGroup.Group("/debug")
+	debugGroup.GET("/goroutines", diagnosticsHandler.Goroutines)
+	debugGroup.GET("/memstats", diagnosticsHandler.MemStats)
+	debugGroup.GET("/buildinfo", diagnosticsHandler.BuildInfo)
+	debugGroup.GET("/config", diagnosticsHandler.Config)
+	registerPprofRoutes(debugGroup.Group("/pprof"))
+
+	return nil
+}
+
+// registerPprofRoutes mounts net/http/pprof. Named profiles are registered
+// one by one because pprof.Index only resolves them under /debug/pprof/.
+func registerPprofRoutes(group *gin.RouterGroup) {
+	group.GET("/", gin.WrapF(pprof.Index))
+	group.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	group.GET("/profile", withProfileDeadline(pprof.Profile, 30*time.Second))
+	group.GET("/symbol", gin.WrapF(pprof.Symbol))
+	group.POST("/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/trace", withProfileDeadline(pprof.Trace, time.Second))
+
+	for _, name := range []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"} {
+		group.GET("/"+name, gin.WrapH(pprof.Handler(name)))
+	}
+}
+
+// profileDeadlineGrace leaves time to write the profile once recorded
+const profileDeadlineGrace = 10 * time.Second
+
+// withProfileDeadline lets CPU profiles and traces record for longer than
+// SERVER_WRITE_TIMEOUT. The write deadline is pushed past the requested
+// duration, fallback when there is no seconds parameter, and net/http/pprof
+// is handed a server without write timeout since it rejects durations
+// exceeding it.
+func withProfileDeadline(handler http.HandlerFunc, fallback time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		duration := fallback
+		if seconds, err := strconv.ParseFloat(c.Query("seconds"), 64); err == nil && seconds > 0 {
+			duration = time.Duration(seconds * float64(time.Second))
+		}
+		// Not every writer supports deadlines, e.g. in tests
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(duration + profileDeadlineGrace))
+
+		ctx := context.WithValue(c.Request.Context(), http.ServerContextKey, &http.Server{})
+		handler(c.Writer, c.Request.WithContext(ctx))
+	}
+}
@@ -0,0 +1,28 @@
+package route
+
+import (
+	"errors"
+
+	"golang-template/api/middleware"
+	"golang-template/configs"
+	"golang-template/infrastructure/logger"
+	"golang-template/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMetricsRoute exposes the Prometheus metrics. They are disabled
+// unless METRICS_ENABLED is set and require METRICS_TOKEN.
+func RegisterMetricsRoute(router *gin.Engine, cfg *configs.Config, log logger.Logger) error {
+	if !cfg.MetricsEnabled {
+		return nil
+	}
+
+	if cfg.MetricsToken == "" {
+		return errors.New("METRICS_ENABLED requires METRICS_TOKEN")
+	}
+
+	router.GET(cfg.MetricsPath, middleware.MetricsAuth(cfg), gin.WrapH(metrics.Handler()))
+	log.Info("Metrics available", "path", cfg.MetricsPath)
+	return nil
+}
@@ -0,0 +1,8 @@
+package route
+
+// Feature modules register themselves with the module registry on import
+import (
+	_ "golang-template/app/module/audit"
+	_ "golang-template/app/module/errorcode"
+	_ "golang-template/app/module/health"
+)
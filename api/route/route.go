@@ -3,19 +3,23 @@ package route
 import (
 	"net/http"
 
+	"golang-template/app/core/module"
 	"golang-template/configs"
 	"golang-template/infrastructure/logger"
+	"golang-template/pkg/common/errors"
 	"golang-template/pkg/common/response"
 
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(router *gin.Engine, cfg *configs.Config, log logger.Logger) {
+func RegisterRoutes(router *gin.Engine, cfg *configs.Config, log logger.Logger, modules *module.Registry) error {
 
-	// API routes group
-	apiGroup := router.Group("/api")
-	{
-		RegisterHealthRoute(apiGroup, cfg, log)
+	if err := RegisterAdminRoutes(router, cfg, log); err != nil {
+		return err
+	}
+
+	if err := RegisterMetricsRoute(router, cfg, log); err != nil {
+		return err
 	}
 
 	RegisterSwaggerRoute(router, cfg, log)
@@ -25,6 +29,13 @@ func RegisterRoutes(router *gin.Engine, cfg *configs.Config, log logger.Logger)
 		c.String(http.StatusOK, "Welcome to GolangTemplate API")
 	})
 
+	// API routes group, last so conflicts with the routes above are
+	// reported with the module name
+	apiGroup := router.Group("/api")
+	if err := modules.RegisterRoutes(apiGroup, router.Routes()); err != nil {
+		return err
+	}
+
 	// 404 handler
 	router.NoRoute(func(c *gin.Context) {
 		if len(c.Request.URL.Path) >= 4 && c.Request.URL.Path[:4] == "/api" {
@@ -36,6 +47,8 @@ func RegisterRoutes(router *gin.Engine, cfg *configs.Config, log logger.Logger)
 
 	// 403 method not allowed
 	router.NoMethod(func(c *gin.Context) {
-		response.ErrorWithCode(c, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method not allowed")
+		response.Error(c, errors.FromCode(errors.CodeMethodNotAllowed, nil))
 	})
+
+	return nil
 }
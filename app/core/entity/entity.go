@@ -15,6 +15,7 @@ type BaseEntity struct {
 	ID        string    `json:"id" firestore:"id"`
 	CreatedAt time.Time `json:"createdAt" firestore:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" firestore:"updatedAt"`
+	Version   int64     `json:"version" firestore:"version"`
 }
 
 func (e *BaseEntity) GetID() string {
@@ -40,3 +41,49 @@ func (e *BaseEntity) SetCreatedAt(t time.Time) {
 func (e *BaseEntity) SetUpdatedAt(t time.Time) {
 	e.UpdatedAt = t
 }
+
+// Versioned entities are protected against concurrent writes: repositories
+// increment the version on every update and reject updates of an older
+// version
+type Versioned interface {
+	GetVersion() int64
+	SetVersion(version int64)
+}
+
+func (e *BaseEntity) GetVersion() int64 {
+	return e.Version
+}
+
+func (e *BaseEntity) SetVersion(version int64) {
+	e.Version = version
+}
+
+// SoftDeletable entities are marked deleted instead of being removed, they
+// are hidden from repository reads until restored or purged
+type SoftDeletable interface {
+	GetDeletedAt() *time.Time
+	GetDeletedBy() string
+	// SetDeleted marks the entity deleted, or restores it when at is nil
+	SetDeleted(at *time.Time, by string)
+}
+
+// SoftDelete makes an entity soft deletable when embedded next to
+// BaseEntity. DeletedAt is stored as null while the entity is live so
+// repositories can filter on it.
+type SoftDelete struct {
+	DeletedAt *time.Time `json:"deletedAt,omitempty" firestore:"deletedAt"`
+	DeletedBy string     `json:"deletedBy,omitempty" firestore:"deletedBy,omitempty"`
+}
+
+func (e *SoftDelete) GetDeletedAt() *time.Time {
+	return e.DeletedAt
+}
+
+func (e *SoftDelete) GetDeletedBy() string {
+	return e.DeletedBy
+}
+
+func (e *SoftDelete) SetDeleted(at *time.Time, by string) {
+	e.DeletedAt = at
+	e.DeletedBy = by
+}
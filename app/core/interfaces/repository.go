@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"golang-template/app/core/entity"
 )
@@ -23,6 +24,10 @@ type Repository[T entity.Entity] interface {
 	// List gets entities with optional pagination and filters
 	List(ctx context.Context, page, limit int, filters map[string]interface{}) ([]T, int64, error)
 
+	// Stream iterates over all entities matching the filters without loading
+	// them in memory, e.g. for exports
+	Stream(ctx context.Context, filters map[string]interface{}) Iterator[T]
+
 	// Count counts entities with optional filters
 	Count(ctx context.Context, filters map[string]interface{}) (int64, error)
 
@@ -33,6 +38,44 @@ type Repository[T entity.Entity] interface {
 	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
+// SoftDeleteRepository is a repository of entity.SoftDeletable entities.
+// Delete marks them deleted and reads skip deleted entities unless the
+// context comes from WithDeleted.
+type SoftDeleteRepository[T entity.Entity] interface {
+	Repository[T]
+
+	// Restore undoes the soft delete of an entity
+	Restore(ctx context.Context, id string) (T, error)
+
+	// Purge permanently deletes an entity, deleted or not
+	Purge(ctx context.Context, id string) error
+
+	// PurgeDeleted permanently deletes the entities soft deleted before the
+	// given time and returns how many were removed
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+}
+
+type withDeletedKey struct{}
+
+// WithDeleted returns a copy of ctx in which repository reads and updates
+// include soft deleted entities
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withDeletedKey{}, true)
+}
+
+// IncludesDeleted reports whether ctx comes from WithDeleted
+func IncludesDeleted(ctx context.Context) bool {
+	included, _ := ctx.Value(withDeletedKey{}).(bool)
+	return included
+}
+
+// Iterator yields items one at a time. Next returns io.EOF after the last
+// item and Stop must be called to release the underlying resources.
+type Iterator[T any] interface {
+	Next() (T, error)
+	Stop()
+}
+
 // Paginator interface for pagination functionality
 type Paginator interface {
 	// GetPage returns the current page number
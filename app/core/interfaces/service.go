@@ -18,6 +18,8 @@ type Service[T entity.Entity, CreateDTO any, UpdateDTO any] interface {
 
 	List(ctx context.Context, paginator Paginator) ([]T, int64, error)
 
+	Stream(ctx context.Context, filter map[string]interface{}) Iterator[T]
+
 	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
@@ -0,0 +1,77 @@
+package module
+
+import (
+	"context"
+
+	"golang-template/configs"
+	"golang-template/infrastructure/firebase"
+	"golang-template/infrastructure/logger"
+	"golang-template/pkg/lifecycle"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Health statuses reported by a HealthChecker
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+	StatusUnknown  = "unknown"
+)
+
+// Module describes a self-contained feature living under app/module
+type Module interface {
+	// Name returns the unique module name used for registration and config toggles
+	Name() string
+
+	// Init wires the module with its dependencies before routes are registered
+	Init(deps Dependencies) error
+
+	// RegisterRoutes mounts the module routes on the API group. It may be
+	// called more than once during route conflict detection.
+	RegisterRoutes(group *gin.RouterGroup)
+
+	// HealthCheckers returns the checks reported by the health endpoint
+	HealthCheckers() []HealthChecker
+
+	// Shutdown releases module resources
+	Shutdown(ctx context.Context) error
+}
+
+// Dependencies are the shared services handed to every module on Init
+type Dependencies struct {
+	Config   *configs.Config
+	Logger   logger.Logger
+	Firebase *firebase.Client
+	Registry *Registry
+	// Lifecycle is nil when running as a serverless function
+	Lifecycle *lifecycle.Manager
+	// AdminAuth guards admin-only module routes with ADMIN_TOKEN
+	AdminAuth gin.HandlerFunc
+}
+
+// HealthChecker reports the status of a single dependency
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) HealthStatus
+}
+
+// HealthStatus is the result of a HealthChecker
+type HealthStatus struct {
+	Status  string
+	Message string
+}
+
+// HealthCheckFunc adapts a function to the HealthChecker interface
+type HealthCheckFunc struct {
+	CheckName string
+	Fn        func(ctx context.Context) HealthStatus
+}
+
+func (h HealthCheckFunc) Name() string {
+	return h.CheckName
+}
+
+func (h HealthCheckFunc) Check(ctx context.Context) HealthStatus {
+	return h.Fn(ctx)
+}
@@ -0,0 +1,259 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang-template/configs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Registry keeps the registered modules in registration order
+type Registry struct {
+	mu      sync.RWMutex
+	modules []Module
+	names   map[string]struct{}
+	active  []Module
+}
+
+var defaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty module registry
+func NewRegistry() *Registry {
+	return &Registry{
+		names: make(map[string]struct{}),
+	}
+}
+
+// Default returns the registry used by Register
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a module to the default registry, typically from a package init
+func Register(m Module) {
+	defaultRegistry.Register(m)
+}
+
+// Register adds a module to the registry and panics on duplicate names
+func (r *Registry) Register(m Module) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if m == nil {
+		panic("module: Register module is nil")
+	}
+
+	name := m.Name()
+	if _, exists := r.names[name]; exists {
+		panic("module: Register called twice for module " + name)
+	}
+
+	r.names[name] = struct{}{}
+	r.modules = append(r.modules, m)
+}
+
+// Modules returns all registered modules
+func (r *Registry) Modules() []Module {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Module(nil), r.modules...)
+}
+
+// Active returns the modules that were enabled and initialized
+func (r *Registry) Active() []Module {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Module(nil), r.active...)
+}
+
+// Init initializes every module enabled by the configuration
+func (r *Registry) Init(deps Dependencies) error {
+	// Modules run without the lock held, so their Init can use the registry
+	r.mu.RLock()
+	modules := append([]Module(nil), r.modules...)
+	err := validateModuleNames(r.names, deps.Config)
+	r.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if deps.Registry == nil {
+		deps.Registry = r
+	}
+
+	active := make([]Module, 0, len(modules))
+	for _, m := range modules {
+		if !isEnabled(m.Name(), deps.Config) {
+			deps.Logger.Info("Module disabled", "module", m.Name())
+			continue
+		}
+
+		if err := m.Init(deps); err != nil {
+			return fmt.Errorf("module %s: init: %w", m.Name(), err)
+		}
+
+		deps.Logger.Debug("Module initialized", "module", m.Name())
+		active = append(active, m)
+	}
+
+	r.mu.Lock()
+	r.active = active
+	r.mu.Unlock()
+	return nil
+}
+
+// RegisterRoutes mounts the routes of every active module on the group.
+// Routes are first registered on a scratch engine so conflicts are reported
+// with the owning module names instead of a gin panic.
+func (r *Registry) RegisterRoutes(group *gin.RouterGroup, existing gin.RoutesInfo) error {
+	active := r.Active()
+
+	if err := detectRouteConflicts(group.BasePath(), existing, active); err != nil {
+		return err
+	}
+
+	for _, m := range active {
+		m.RegisterRoutes(group)
+	}
+
+	return nil
+}
+
+// HealthCheckers collects the health checkers of every active module
+func (r *Registry) HealthCheckers() []HealthChecker {
+	var checkers []HealthChecker
+	for _, m := range r.Active() {
+		checkers = append(checkers, m.HealthCheckers()...)
+	}
+	return checkers
+}
+
+// Shutdown shuts down active modules in reverse initialization order
+func (r *Registry) Shutdown(ctx context.Context) error {
+	active := r.Active()
+
+	var errs []error
+	for i := len(active) - 1; i >= 0; i-- {
+		if err := active[i].Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("module %s: shutdown: %w", active[i].Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// routerOwner owns the routes registered outside modules, e.g. admin,
+// metrics and swagger
+const routerOwner = "the router (api/route)"
+
+// detectRouteConflicts registers all modules on a scratch engine seeded
+// with the existing routes and reports routes claimed twice
+func detectRouteConflicts(basePath string, existing gin.RoutesInfo, modules []Module) (err error) {
+	mode := gin.Mode()
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(mode)
+
+	engine := gin.New()
+	group := engine.Group(basePath)
+
+	owners := make(map[string]string)
+	current := ""
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("module %s: route conflict: %v", current, rec)
+			if owner := routeOwner(owners, fmt.Sprint(rec), "module "+current); owner != "" {
+				err = fmt.Errorf("route conflict between %s and module %s: %v", owner, current, rec)
+			}
+		}
+	}()
+
+	for _, route := range existing {
+		engine.Handle(route.Method, route.Path, func(*gin.Context) {})
+		owners[route.Method+" "+route.Path] = routerOwner
+	}
+
+	for _, m := range modules {
+		current = m.Name()
+
+		m.RegisterRoutes(group)
+
+		// engine.Routes walks the routing trees, so the routes of this
+		// module are the ones without an owner yet rather than the last ones
+		for _, route := range engine.Routes() {
+			key := route.Method + " " + route.Path
+			if _, exists := owners[key]; !exists {
+				owners[key] = "module " + current
+			}
+		}
+	}
+
+	return nil
+}
+
+// routeOwner finds the owner of a path quoted in a gin route panic
+func routeOwner(owners map[string]string, message, current string) string {
+	for key, owner := range owners {
+		if owner == current {
+			continue
+		}
+		path := key[strings.IndexByte(key, ' ')+1:]
+		if strings.Contains(message, "'"+path+"'") {
+			return owner
+		}
+	}
+	return ""
+}
+
+// isEnabled reports whether a module is enabled by MODULES_ENABLED and MODULES_DISABLED
+func isEnabled(name string, cfg *configs.Config) bool {
+	if cfg == nil {
+		return true
+	}
+
+	for _, disabled := range cfg.ModulesDisabled {
+		if strings.TrimSpace(disabled) == name {
+			return false
+		}
+	}
+
+	if len(cfg.ModulesEnabled) == 0 {
+		return true
+	}
+
+	for _, enabled := range cfg.ModulesEnabled {
+		if strings.TrimSpace(enabled) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateModuleNames rejects toggles that reference unknown modules
+func validateModuleNames(names map[string]struct{}, cfg *configs.Config) error {
+	if cfg == nil {
+		return nil
+	}
+
+	for _, list := range [][]string{cfg.ModulesEnabled, cfg.ModulesDisabled} {
+		for _, name := range list {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if _, exists := names[name]; !exists {
+				return fmt.Errorf("unknown module %q in configuration", name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package module
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-template/configs"
+	"golang-template/infrastructure/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testModule struct {
+	name   string
+	routes []string
+	init   func(deps Dependencies) error
+}
+
+func (m *testModule) Name() string { return m.name }
+
+func (m *testModule) Init(deps Dependencies) error {
+	if m.init != nil {
+		return m.init(deps)
+	}
+	return nil
+}
+
+func (m *testModule) RegisterRoutes(group *gin.RouterGroup) {
+	for _, path := range m.routes {
+		group.GET(path, func(*gin.Context) {})
+	}
+}
+
+func (m *testModule) HealthCheckers() []HealthChecker { return nil }
+
+func (m *testModule) Shutdown(context.Context) error { return nil }
+
+func TestRegistryInitCanUseRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&testModule{name: "first"})
+	r.Register(&testModule{name: "second", init: func(deps Dependencies) error {
+		deps.Registry.Active()
+		deps.Registry.HealthCheckers()
+		return nil
+	}})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Init(Dependencies{Logger: logger.Default()})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Init() deadlocked")
+	}
+
+	if got := len(r.Active()); got != 2 {
+		t.Errorf("len(Active()) = %d, want 2", got)
+	}
+}
+
+func TestRegistryInitToggles(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *configs.Config
+		want    []string
+		wantErr string
+	}{
+		{name: "all", cfg: &configs.Config{}, want: []string{"a", "b"}},
+		{name: "enabled", cfg: &configs.Config{ModulesEnabled: []string{" b"}}, want: []string{"b"}},
+		{name: "disabled", cfg: &configs.Config{ModulesDisabled: []string{"a"}}, want: []string{"b"}},
+		{name: "unknown", cfg: &configs.Config{ModulesDisabled: []string{"c"}}, wantErr: `unknown module "c"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			r.Register(&testModule{name: "a"})
+			r.Register(&testModule{name: "b"})
+
+			err := r.Init(Dependencies{Config: tt.cfg, Logger: logger.Default()})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Init() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+
+			var names []string
+			for _, m := range r.Active() {
+				names = append(names, m.Name())
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Active() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectRouteConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing gin.RoutesInfo
+		modules  []Module
+		wantErr  string
+	}{
+		{
+			name: "distinct routes",
+			modules: []Module{
+				&testModule{name: "users", routes: []string{"/users"}},
+				&testModule{name: "health", routes: []string{"/health"}},
+			},
+		},
+		{
+			name: "two modules",
+			modules: []Module{
+				&testModule{name: "users", routes: []string{"/users"}},
+				&testModule{name: "accounts", routes: []string{"/users"}},
+			},
+			wantErr: "route conflict between module users and module accounts",
+		},
+		{
+			name:     "router route",
+			existing: gin.RoutesInfo{{Method: "GET", Path: "/api/health"}},
+			modules: []Module{
+				&testModule{name: "health", routes: []string{"/health"}},
+			},
+			wantErr: "route conflict between the router (api/route) and module health",
+		},
+		{
+			name: "wildcard",
+			modules: []Module{
+				&testModule{name: "users", routes: []string{"/users/:id"}},
+				&testModule{name: "profiles", routes: []string{"/users/:uid"}},
+			},
+			wantErr: "module profiles",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := detectRouteConflicts("/api", tt.existing, tt.modules)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("detectRouteConflicts() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("detectRouteConflicts() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
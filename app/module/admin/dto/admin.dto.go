@@ -0,0 +1,33 @@
+package dto
+
+import "golang-template/infrastructure/logger"
+
+type LogLevelResponse struct {
+	// Root logger level
+	Root logger.LevelState `json:"root"`
+	// Overrides of named loggers
+	Loggers map[string]logger.LevelState `json:"loggers"`
+}
+
+type UpdateLogLevelRequest struct {
+	// Named logger to change, empty for the root logger
+	Logger string `json:"logger" example:"firestore"`
+	// New level: debug, info, warn, error
+	Level string `json:"level" example:"debug"`
+	// Optional duration after which the previous level is restored
+	TTL string `json:"ttl,omitempty" example:"10m"`
+	// Remove the override of a named logger instead of setting a level
+	Reset bool `json:"reset,omitempty"`
+}
+
+type BuildInfoResponse struct {
+	// Go version used to build the binary
+	GoVersion string `json:"goVersion" example:"go1.22.2"`
+	// Main module path and version
+	Path    string `json:"path" example:"golang-template"`
+	Version string `json:"version" example:"(devel)"`
+	// Build settings such as vcs.revision and vcs.time
+	Settings map[string]string `json:"settings"`
+	// Dependencies and their versions
+	Dependencies map[string]string `json:"dependencies"`
+}
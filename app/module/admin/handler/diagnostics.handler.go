@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+
+	"golang-template/app/module/admin/dto"
+	"golang-template/configs"
+	"golang-template/pkg/common/errors"
+	"golang-template/pkg/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DiagnosticsHandler struct {
+	config *configs.Config
+}
+
+func NewDiagnosticsHandler(config *configs.Config) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		config: config,
+	}
+}
+
+// Goroutines writes the stack of every goroutine as plain text
+func (h *DiagnosticsHandler) Goroutines(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	if err := pprof.Lookup("goroutine").WriteTo(c.Writer, 2); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// MemStats returns the runtime memory statistics
+func (h *DiagnosticsHandler) MemStats(c *gin.Context) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	response.OK(c, stats)
+}
+
+// BuildInfo returns the Go version, VCS revision and dependencies the
+// binary was built with
+func (h *DiagnosticsHandler) BuildInfo(c *gin.Context) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		response.Error(c, errors.FromCode(errors.CodeBuildInfoUnavailable, nil))
+		return
+	}
+
+	settings := make(map[string]string, len(info.Settings))
+	for _, setting := range info.Settings {
+		settings[setting.Key] = setting.Value
+	}
+
+	dependencies := make(map[string]string, len(info.Deps))
+	for _, dep := range info.Deps {
+		version := dep.Version
+		if dep.Replace != nil {
+			version = dep.Replace.Path + "@" + dep.Replace.Version
+		}
+		dependencies[dep.Path] = version
+	}
+
+	response.OK(c, dto.BuildInfoResponse{
+		GoVersion:    info.GoVersion,
+		Path:         info.Main.Path,
+		Version:      info.Main.Version,
+		Settings:     settings,
+		Dependencies: dependencies,
+	})
+}
+
+// Config returns the effective configuration with secrets masked
+func (h *DiagnosticsHandler) Config(c *gin.Context) {
+	response.OK(c, h.config.Redacted())
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"time"
+
+	"golang-template/app/module/admin/dto"
+	"golang-template/infrastructure/logger"
+	"golang-template/pkg/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LogLevelHandler struct {
+	levels *logger.Levels
+}
+
+func NewLogLevelHandler(levels *logger.Levels) *LogLevelHandler {
+	return &LogLevelHandler{
+		levels: levels,
+	}
+}
+
+// Get returns the root level and the named logger overrides
+func (h *LogLevelHandler) Get(c *gin.Context) {
+	root, named := h.levels.States()
+
+	response.OK(c, dto.LogLevelResponse{
+		Root:    root,
+		Loggers: named,
+	})
+}
+
+// Update changes a log level at runtime, optionally reverting after a TTL
+func (h *LogLevelHandler) Update(c *gin.Context) {
+	var req dto.UpdateLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	log := logger.FromContext(c.Request.Context())
+
+	if req.Reset {
+		h.levels.Reset(req.Logger)
+		log.Info("Log level reset", "logger", req.Logger)
+		h.Get(c)
+		return
+	}
+
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		response.ValidationError(c, "level", err.Error())
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl < 0 {
+			response.ValidationError(c, "ttl", "ttl must be a positive duration such as 10m")
+			return
+		}
+	}
+
+	h.levels.Set(req.Logger, level, ttl)
+	log.Info("Log level changed", "logger", req.Logger, "level", level.String(), "ttl", ttl)
+
+	h.Get(c)
+}
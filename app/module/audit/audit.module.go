@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"golang-template/app/core/module"
+	"golang-template/app/module/audit/handler"
+	"golang-template/pkg/common/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	module.Register(&Module{})
+}
+
+// Module exposes the audit trail of repository changes. Like the other
+// admin endpoints it is only mounted with ADMIN_ENABLED and requires
+// ADMIN_TOKEN.
+type Module struct {
+	enabled   bool
+	adminAuth gin.HandlerFunc
+	handler   *handler.AuditHandler
+}
+
+func (m *Module) Name() string {
+	return "audit"
+}
+
+func (m *Module) Init(deps module.Dependencies) error {
+	m.enabled = deps.Config != nil && deps.Config.AdminEnabled
+	if !m.enabled {
+		return nil
+	}
+	if deps.AdminAuth == nil {
+		return errors.New("admin auth middleware is required")
+	}
+	m.adminAuth = deps.AdminAuth
+
+	// Without Firebase or with auditing disabled the endpoint answers 503
+	var store audit.Store
+	if deps.Firebase != nil {
+		store = deps.Firebase.Audit
+	}
+	m.handler = handler.NewAuditHandler(store)
+	return nil
+}
+
+func (m *Module) RegisterRoutes(group *gin.RouterGroup) {
+	if !m.enabled {
+		return
+	}
+	group.GET("/audit/events", m.adminAuth, m.handler.List)
+}
+
+func (m *Module) HealthCheckers() []module.HealthChecker {
+	return nil
+}
+
+func (m *Module) Shutdown(ctx context.Context) error {
+	return nil
+}
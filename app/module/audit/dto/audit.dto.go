@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"golang-template/pkg/common/audit"
+)
+
+// AuditEventResponse is one recorded change of an entity
+type AuditEventResponse struct {
+	ID string `json:"id" example:"mJ4nq2bX7kLw9PzR1cVt"`
+	// Action: create, update, delete, restore or purge
+	Action string `json:"action" example:"update"`
+	// Collection of the entity
+	Entity   string `json:"entity" example:"users"`
+	EntityID string `json:"entityId" example:"u_123"`
+	// uid of the request principal, "system" for server jobs
+	Actor     string `json:"actor,omitempty" example:"8f14e45fceea167a"`
+	RequestID string `json:"requestId,omitempty" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	// Changed fields with their values before and after
+	Changes   map[string]audit.Change `json:"changes,omitempty"`
+	Timestamp time.Time               `json:"timestamp" example:"2024-05-01T12:00:00Z"`
+}
+
+// AuditEventListResponse is a page of events, newest first
+type AuditEventListResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	// Cursor of the next page, absent on the last page
+	NextCursor string `json:"nextCursor,omitempty" example:"mJ4nq2bX7kLw9PzR1cVt"`
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"golang-template/app/module/audit/dto"
+	"golang-template/pkg/common/audit"
+	"golang-template/pkg/common/errors"
+	"golang-template/pkg/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	store audit.Store
+}
+
+// NewAuditHandler creates the handler querying the audit trail, store is
+// nil when auditing is unavailable
+func NewAuditHandler(store audit.Store) *AuditHandler {
+	return &AuditHandler{
+		store: store,
+	}
+}
+
+// List returns the events filtered by entity, entityId, actor and the
+// RFC 3339 time range [from, to), paginated with limit and cursor
+func (h *AuditHandler) List(c *gin.Context) {
+	if h.store == nil {
+		response.Error(c, errors.FromCode(errors.CodeServiceUnavailable, nil))
+		return
+	}
+
+	filter := audit.Filter{
+		Entity:   c.Query("entity"),
+		EntityID: c.Query("entityId"),
+		Actor:    c.Query("actor"),
+		Cursor:   c.Query("cursor"),
+	}
+
+	var err error
+	if filter.From, err = parseTime(c.Query("from")); err != nil {
+		response.ValidationError(c, "from", "from must be an RFC 3339 time such as 2024-05-01T00:00:00Z")
+		return
+	}
+	if filter.To, err = parseTime(c.Query("to")); err != nil {
+		response.ValidationError(c, "to", "to must be an RFC 3339 time such as 2024-05-02T00:00:00Z")
+		return
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		response.ValidationError(c, "to", "to must be after from")
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 1 {
+			response.ValidationError(c, "limit", "limit must be a positive number")
+			return
+		}
+	}
+
+	events, next, err := h.store.Query(c.Request.Context(), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	items := make([]dto.AuditEventResponse, 0, len(events))
+	for _, event := range events {
+		items = append(items, dto.AuditEventResponse{
+			ID:        event.ID,
+			Action:    event.Action,
+			Entity:    event.Entity,
+			EntityID:  event.EntityID,
+			Actor:     event.Actor,
+			RequestID: event.RequestID,
+			Changes:   event.Changes,
+			Timestamp: event.Timestamp,
+		})
+	}
+
+	response.OK(c, dto.AuditEventListResponse{
+		Events:     items,
+		NextCursor: next,
+	})
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang-template/pkg/common/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeStore struct {
+	filter audit.Filter
+}
+
+func (s *fakeStore) Record(context.Context, audit.Event) error { return nil }
+
+func (s *fakeStore) Query(_ context.Context, filter audit.Filter) ([]audit.Event, string, error) {
+	s.filter = filter
+	return []audit.Event{{ID: "e1", Action: audit.ActionCreate}}, "e1", nil
+}
+
+func serveList(store audit.Store, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if store == nil {
+		router.GET("/events", NewAuditHandler(nil).List)
+	} else {
+		router.GET("/events", NewAuditHandler(store).List)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?"+query, nil))
+	return rec
+}
+
+func TestAuditHandlerList(t *testing.T) {
+	store := &fakeStore{}
+	rec := serveList(store, "entity=users&entityId=u1&actor=a&from=2024-05-01T00:00:00Z&to=2024-05-02T00:00:00Z&limit=5&cursor=c")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	want := audit.Filter{
+		Entity:   "users",
+		EntityID: "u1",
+		Actor:    "a",
+		From:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+		Limit:    5,
+		Cursor:   "c",
+	}
+	if !store.filter.From.Equal(want.From) || !store.filter.To.Equal(want.To) {
+		t.Errorf("filter range = %v to %v, want %v to %v", store.filter.From, store.filter.To, want.From, want.To)
+	}
+	store.filter.From, store.filter.To, want.From, want.To = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+	if store.filter != want {
+		t.Errorf("filter = %+v, want %+v", store.filter, want)
+	}
+}
+
+func TestAuditHandlerListValidation(t *testing.T) {
+	tests := map[string]string{
+		"from":     "from=yesterday",
+		"to":       "to=2024-05-02",
+		"range":    "from=2024-05-02T00:00:00Z&to=2024-05-01T00:00:00Z",
+		"empty":    "from=2024-05-01T00:00:00Z&to=2024-05-01T00:00:00Z",
+		"limit":    "limit=0",
+		"no limit": "limit=ten",
+	}
+
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			if rec := serveList(&fakeStore{}, query); rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", rec.Code)
+			}
+		})
+	}
+}
+
+func TestAuditHandlerUnavailable(t *testing.T) {
+	if rec := serveList(nil, ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,19 @@
+package dto
+
+// ErrorCodeResponse documents an error code of the catalog
+type ErrorCodeResponse struct {
+	// Stable error code returned in error responses
+	Code string `json:"code" example:"NOT_FOUND"`
+	// HTTP status returned with the code
+	Status int `json:"status" example:"404"`
+	// HTTP status text
+	Title string `json:"title" example:"Not Found"`
+	// Default message in the requested language
+	Message string `json:"message" example:"Resource not found"`
+	// Message template, {name} is replaced by a parameter
+	Template string `json:"template" example:"{resource|Resource} not found"`
+	// Documentation URL, also used as the problem details type
+	Type string `json:"type" example:"https://docs.example.com/errors/not-found"`
+	// Languages with a translated message
+	Languages []string `json:"languages"`
+}
@@ -0,0 +1,41 @@
+package errorcode
+
+import (
+	"context"
+
+	"golang-template/app/core/module"
+	"golang-template/app/module/errorcode/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	module.Register(&Module{})
+}
+
+// Module exposes the reference of the error codes in the catalog
+type Module struct {
+	handler *handler.ErrorCodeHandler
+}
+
+func (m *Module) Name() string {
+	return "errorcode"
+}
+
+func (m *Module) Init(deps module.Dependencies) error {
+	m.handler = handler.NewErrorCodeHandler()
+	return nil
+}
+
+func (m *Module) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/errors", m.handler.List)
+	group.GET("/errors/:code", m.handler.Get)
+}
+
+func (m *Module) HealthCheckers() []module.HealthChecker {
+	return nil
+}
+
+func (m *Module) Shutdown(ctx context.Context) error {
+	return nil
+}
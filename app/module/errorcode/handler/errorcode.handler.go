@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"sort"
+
+	"golang-template/app/module/errorcode/dto"
+	"golang-template/pkg/common/errors"
+	"golang-template/pkg/common/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ErrorCodeHandler struct{}
+
+// NewErrorCodeHandler creates the handler generating the error code
+// reference from the catalog
+func NewErrorCodeHandler() *ErrorCodeHandler {
+	return &ErrorCodeHandler{}
+}
+
+// List returns every registered error code, messages are localized with
+// Accept-Language
+func (h *ErrorCodeHandler) List(c *gin.Context) {
+	defs := errors.Definitions()
+	acceptLanguage := c.GetHeader("Accept-Language")
+
+	codes := make([]dto.ErrorCodeResponse, 0, len(defs))
+	for _, def := range defs {
+		codes = append(codes, toErrorCodeResponse(def, acceptLanguage))
+	}
+
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	response.OK(c, codes)
+}
+
+// Get returns a single error code
+func (h *ErrorCodeHandler) Get(c *gin.Context) {
+	def, ok := errors.Lookup(c.Param("code"))
+	if !ok {
+		response.Error(c, errors.NotFound("Error code", c.Param("code")))
+		return
+	}
+
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	response.OK(c, toErrorCodeResponse(def, c.GetHeader("Accept-Language")))
+}
+
+func toErrorCodeResponse(def errors.Definition, acceptLanguage string) dto.ErrorCodeResponse {
+	message, _, _ := errors.Localize(def.Code, acceptLanguage, nil)
+
+	languages := []string{errors.DefaultLanguage.String()}
+	for lang := range def.Translations {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages[1:])
+
+	return dto.ErrorCodeResponse{
+		Code:      def.Code,
+		Status:    def.Status,
+		Title:     errors.StatusText(def.Status),
+		Message:   message,
+		Template:  def.Message,
+		Type:      response.ProblemType(def.Code),
+		Languages: languages,
+	}
+}
@@ -3,30 +3,53 @@ package handler
 import (
 	"golang-template/app/module/health/service"
 	"golang-template/infrastructure/logger"
+	"golang-template/pkg/common/errors"
 	"golang-template/pkg/common/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+// CodeNotReady is returned by the readiness probe while starting up or
+// shutting down
+const CodeNotReady = "NOT_READY"
+
 type HealthHandler struct {
-	logger  logger.Logger
 	service service.HealthService
+	ready   func() bool
 }
 
-func NewHealthHandler(logger logger.Logger, service service.HealthService) *HealthHandler {
+// NewHealthHandler creates the health handler. ready may be nil when the
+// application has no lifecycle, in which case it is always ready.
+func NewHealthHandler(service service.HealthService, ready func() bool) *HealthHandler {
 	return &HealthHandler{
-		logger:  logger,
 		service: service,
+		ready:   ready,
 	}
 }
 
 func (h *HealthHandler) Check(c *gin.Context) {
 	result, err := h.service.Check(c.Request.Context())
 	if err != nil {
-		h.logger.Error("Health check failed", "error", err)
+		logger.FromContext(c.Request.Context()).Error("Health check failed", "error", err)
 		response.Error(c, err)
 		return
 	}
 
 	response.OK(c, result)
 }
+
+// Live reports that the process is running
+func (h *HealthHandler) Live(c *gin.Context) {
+	response.OK(c, gin.H{"status": "alive"})
+}
+
+// Ready reports whether the server accepts traffic. It fails while starting
+// up and as soon as a graceful shutdown begins.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.ready != nil && !h.ready() {
+		response.Error(c, errors.FromCode(CodeNotReady, nil))
+		return
+	}
+
+	response.OK(c, gin.H{"status": "ready"})
+}
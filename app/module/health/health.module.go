@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"net/http"
+
+	"golang-template/app/core/module"
+	"golang-template/app/module/health/handler"
+	"golang-template/app/module/health/service"
+	"golang-template/infrastructure/firebase"
+	"golang-template/pkg/common/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	module.Register(&Module{})
+	errors.Register(errors.Definition{
+		Code:    handler.CodeNotReady,
+		Status:  http.StatusServiceUnavailable,
+		Message: "Server is not ready",
+		Translations: map[string]string{
+			"id": "Server belum siap",
+		},
+	})
+}
+
+// Module exposes the health check endpoint
+type Module struct {
+	firebase *firebase.Client
+	handler  *handler.HealthHandler
+}
+
+func (m *Module) Name() string {
+	return "health"
+}
+
+func (m *Module) Init(deps module.Dependencies) error {
+	m.firebase = deps.Firebase
+	healthService := service.NewHealthService(deps.Config, deps.Registry)
+
+	var ready func() bool
+	if deps.Lifecycle != nil {
+		ready = deps.Lifecycle.Ready
+	}
+	m.handler = handler.NewHealthHandler(healthService, ready)
+	return nil
+}
+
+func (m *Module) RegisterRoutes(group *gin.RouterGroup) {
+	group.GET("/health", m.handler.Check)
+	group.GET("/health/live", m.handler.Live)
+	group.GET("/health/ready", m.handler.Ready)
+}
+
+func (m *Module) HealthCheckers() []module.HealthChecker {
+	return []module.HealthChecker{
+		service.NewFirebaseChecker(m.firebase),
+	}
+}
+
+func (m *Module) Shutdown(ctx context.Context) error {
+	return nil
+}
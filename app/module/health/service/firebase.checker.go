@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"golang-template/app/core/module"
+	"golang-template/infrastructure/firebase"
+	"golang-template/infrastructure/logger"
+)
+
+type firebaseChecker struct {
+	firebase *firebase.Client
+}
+
+// NewFirebaseChecker reports the Firebase and Firestore connection status
+func NewFirebaseChecker(firebaseClient *firebase.Client) module.HealthChecker {
+	return &firebaseChecker{firebase: firebaseClient}
+}
+
+func (f *firebaseChecker) Name() string {
+	return "firebase"
+}
+
+func (f *firebaseChecker) Check(ctx context.Context) module.HealthStatus {
+	if f.firebase == nil || f.firebase.App == nil {
+		return module.HealthStatus{
+			Status:  module.StatusUnknown,
+			Message: "Firebase not configured",
+		}
+	}
+
+	if f.firebase.Firestore == nil {
+		return module.HealthStatus{
+			Status:  module.StatusDegraded,
+			Message: "Firestore client not initialized",
+		}
+	}
+
+	if _, err := f.firebase.Firestore.Collections(ctx).GetAll(); err != nil {
+		logger.FromContext(ctx).Warn("Firestore health check failed", "error", err)
+		return module.HealthStatus{
+			Status:  module.StatusDegraded,
+			Message: "Firestore connection issue",
+		}
+	}
+
+	return module.HealthStatus{Status: module.StatusOK}
+}
@@ -5,9 +5,13 @@ import (
 	"os"
 	"time"
 
+	"golang-template/app/core/module"
 	"golang-template/app/module/health/dto"
 	"golang-template/configs"
-	"golang-template/infrastructure/firebase"
+	"golang-template/infrastructure/metrics"
+	"golang-template/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type HealthService interface {
@@ -16,62 +20,54 @@ type HealthService interface {
 
 type healthServiceImpl struct {
 	config     *configs.Config
-	firebase   *firebase.Client
+	registry   *module.Registry
 	startTime  time.Time
 	appVersion string
 }
 
-func NewHealthService(config *configs.Config, firebaseClient *firebase.Client) HealthService {
+func NewHealthService(config *configs.Config, registry *module.Registry) HealthService {
 	return &healthServiceImpl{
 		config:    config,
-		firebase:  firebaseClient,
+		registry:  registry,
 		startTime: time.Now(),
 	}
 }
 
 func (h *healthServiceImpl) Check(ctx context.Context) (*dto.HealthResponse, error) {
-	firebaseStatus := dto.Status{Status: "ok"}
-
-	if h.firebase != nil && h.firebase.App != nil {
-		if h.firebase.Firestore != nil {
-			_, err := h.firebase.Firestore.Collections(ctx).GetAll()
-			if err != nil {
-				firebaseStatus = dto.Status{
-					Status:  "degraded",
-					Message: "Firestore connection issue",
-				}
-			}
-		} else {
-			firebaseStatus = dto.Status{
-				Status:  "degraded",
-				Message: "Firestore client not initialized",
-			}
-		}
-	} else {
-		firebaseStatus = dto.Status{
-			Status:  "unknown",
-			Message: "Firebase not configured",
-		}
-	}
-
 	hostname, _ := os.Hostname()
 
 	uptime := time.Since(h.startTime).String()
 
 	services := map[string]dto.Status{
-		"firebase":            firebaseStatus,
-		"golang-template/api": {Status: "ok"},
+		"golang-template/api": {Status: module.StatusOK},
 		"system": {
-			Status:  "ok",
+			Status:  module.StatusOK,
 			Message: hostname,
 		},
 	}
 
+	// Checks contributed by the registered modules
+	if h.registry != nil {
+		for _, checker := range h.registry.HealthCheckers() {
+			checkCtx, span := tracing.Start(ctx, "HealthCheck "+checker.Name())
+			start := time.Now()
+			result := checker.Check(checkCtx)
+			metrics.HealthCheck(checker.Name(), result.Status, time.Since(start))
+			span.SetAttributes(attribute.String("health.status", result.Status))
+			span.End()
+
+			services[checker.Name()] = dto.Status{
+				Status:  result.Status,
+				Message: result.Message,
+			}
+		}
+	}
+
 	status := "healthy"
 	for _, s := range services {
-		if s.Status == "degraded" {
+		if s.Status == module.StatusDegraded {
 			status = "degraded"
-		} else if s.Status == "down" {
+		} else if s.Status == module.StatusDown {
 			status = "unhealthy"
 			break
 		}
@@ -3,16 +3,19 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
 	"golang-template/api"
+	"golang-template/api/middleware"
+	"golang-template/app/core/module"
 	"golang-template/configs"
 	"golang-template/infrastructure/firebase"
 	"golang-template/infrastructure/logger"
+	httpserver "golang-template/infrastructure/server"
+	"golang-template/infrastructure/tracing"
+	"golang-template/pkg/lifecycle"
 )
 
 // @title GolangTemplate API
@@ -20,52 +23,179 @@ func main() {
 	// Setup configuration
 	cfg := configs.LoadConfig()
 
-	// Initialize logger
-	log := logger.NewLogger(cfg.Debug)
+	// Initialize logger, a misconfigured logger fails startup
+	log, err := logger.NewLoggerFromConfigE(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to create logger:", err)
+		os.Exit(1)
+	}
+	logger.SetDefault(log)
 	log.Info("Starting API server", "name", cfg.AppName, "env", cfg.Environment)
 
+	lc := lifecycle.New(log, lifecycle.Options{
+		PreStopDelay: cfg.ShutdownPreStopDelay,
+		HookTimeout:  cfg.ShutdownHookTimeout,
+	})
+
+	var (
+		fbClient *firebase.Client
+		modules  = module.Default()
+		server   *httpserver.Server
+	)
+
+	// Flush buffered logs last
+	lc.Append(lifecycle.Hook{
+		Name: "logger",
+		OnStop: func(ctx context.Context) error {
+			// Syncing stdout fails on some platforms, nothing to report
+			_ = log.ZapLogger().Sync()
+			return nil
+		},
+	})
+
+	// Install the tracer provider, flush pending spans on shutdown
+	var shutdownTracing func(ctx context.Context) error
+	lc.Append(lifecycle.Hook{
+		Name:      "tracing",
+		DependsOn: []string{"logger"},
+		OnStart: func(ctx context.Context) error {
+			var err error
+			shutdownTracing, err = tracing.Setup(ctx, cfg, log)
+			return err
+		},
+		OnStop: func(ctx context.Context) error {
+			return shutdownTracing(ctx)
+		},
+	})
+
 	// Initialize Firebase client
-	fbClient, err := firebase.Initialize(cfg, log)
-	if err != nil {
-		log.Warn("Firebase initialization failed", "error", err)
-	} else {
-		defer fbClient.Close()
+	lc.Append(lifecycle.Hook{
+		Name:      "firebase",
+		DependsOn: []string{"tracing"},
+		OnStart: func(ctx context.Context) error {
+			var err error
+			fbClient, err = firebase.Initialize(cfg, log)
+			if err != nil {
+				log.Warn("Firebase initialization failed", "error", err)
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if fbClient != nil {
+				fbClient.Close()
+			}
+			return nil
+		},
+	})
+
+	// Initialize feature modules
+	lc.Append(lifecycle.Hook{
+		Name:      "modules",
+		DependsOn: []string{"firebase"},
+		OnStart: func(ctx context.Context) error {
+			return modules.Init(module.Dependencies{
+				Config:    cfg,
+				Logger:    log,
+				Firebase:  fbClient,
+				Lifecycle: lc,
+				AdminAuth: middleware.AdminAuth(cfg),
+			})
+		},
+		OnStop: func(ctx context.Context) error {
+			return modules.Shutdown(ctx)
+		},
+	})
+
+	// Purge soft deleted entities past the retention window
+	lc.Append(lifecycle.Hook{
+		Name:      "soft-delete-purge",
+		DependsOn: []string{"modules"},
+		OnStart: func(ctx context.Context) error {
+			if fbClient != nil && cfg.SoftDeletePurgeInterval > 0 {
+				lc.Go("soft-delete-purge", func(ctx context.Context) {
+					fbClient.RunPurge(ctx, cfg.SoftDeletePurgeInterval, cfg.SoftDeleteRetention)
+				})
+			}
+			return nil
+		},
+	})
+
+	// Start the HTTP server
+	lc.Append(lifecycle.Hook{
+		Name:      "http",
+		DependsOn: []string{"modules"},
+		Timeout:   cfg.ShutdownTimeout,
+		OnStart: func(ctx context.Context) error {
+			router, err := api.SetupRouter(cfg, log, modules)
+			if err != nil {
+				return err
+			}
+
+			server, err = httpserver.New(cfg, router, log)
+			if err != nil {
+				return err
+			}
+
+			listeners, err := httpserver.Listen(cfg, log)
+			if err != nil {
+				return err
+			}
+
+			// Hand the listeners to a new binary on SIGUSR2
+			if cfg.GracefulRestart {
+				lc.Go("graceful-restart", func(ctx context.Context) {
+					httpserver.WatchRestartSignal(ctx, func() {
+						log.Info("Graceful restart requested")
+						if err := httpserver.Restart(listeners, cfg.GracefulRestartTimeout, log); err != nil {
+							log.Error("Graceful restart failed", "error", err)
+							return
+						}
+						lc.Handoff()
+					})
+				})
+			}
+
+			for _, listener := range listeners {
+				go func(listener net.Listener) {
+					log.Info("Server listening", "address", listener.Addr().String(), "tls", cfg.TLSCertFile != "")
+					if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+						lc.Abort(err)
+					}
+				}(listener)
+			}
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("Shutting down server...")
+			return server.Shutdown(ctx)
+		},
+	})
+
+	if err := lc.Start(context.Background()); err != nil {
+		log.Error("Failed to start server", "error", err)
+		os.Exit(1)
 	}
 
-	// Setup HTTP router
-	router := api.SetupRouter(cfg, log)
+	// Tell the previous process we took over its listeners
+	if err := httpserver.NotifyReady(); err != nil {
+		log.Error("Failed to notify parent process", "error", err)
+	}
 
-	// Create HTTP server
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	waitErr := lc.Wait()
+	if waitErr != nil {
+		log.Error("Server stopped unexpectedly", "error", waitErr)
 	}
 
-	// Start the server in a goroutine
-	go func() {
-		log.Info("Server listening", "port", cfg.Port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Error("Failed to start server", "error", err)
-			os.Exit(1)
-		}
-	}()
-
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Info("Shutting down server...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Attempt graceful shutdown
-	if err := server.Shutdown(ctx); err != nil {
+	// Attempt graceful shutdown, every step is bounded by its own deadline
+	if err := lc.Shutdown(context.Background()); err != nil {
 		log.Error("Server forced to shutdown", "error", err)
 		os.Exit(1)
 	}
 
+	if waitErr != nil {
+		os.Exit(1)
+	}
+
 	log.Info("Server exited gracefully")
 }
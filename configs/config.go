@@ -17,10 +17,91 @@ type Config struct {
 	Port        int
 	Debug       bool
 
+	// HTTP Server
+	ServerListeners         []string
+	ServerUnixSocket        string
+	ServerUnixSocketMode    os.FileMode
+	ServerReadTimeout       time.Duration
+	ServerReadHeaderTimeout time.Duration
+	ServerWriteTimeout      time.Duration
+	ServerIdleTimeout       time.Duration
+	ServerMaxHeaderBytes    int
+	ServerKeepAlives        bool
+	ServerH2C               bool
+
+	// TLS
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	TLSClientAuth     string
+	TLSReloadInterval time.Duration
+
+	// Graceful restart
+	GracefulRestart        bool
+	GracefulRestartTimeout time.Duration
+
+	// Shutdown
+	ShutdownTimeout      time.Duration
+	ShutdownPreStopDelay time.Duration
+	ShutdownHookTimeout  time.Duration
+
+	// Logging
+	LogLevel           string
+	LogFormat          string
+	LogOutputPath      string
+	LogSinks           string
+	LogRedact          bool
+	LogRedactKeys      []string
+	LogRedactDetectors []string
+	LogAccessHeaders   []string
+	// Access log
+	LogAccessFields        []string
+	LogAccessSkipPaths     []string
+	LogAccessSlowThreshold time.Duration
+	LogAccessLevels        []string
+	// Log rotation for file sinks
+	LogRotateMaxSizeMB  int
+	LogRotateInterval   time.Duration
+	LogRotateMaxAge     time.Duration
+	LogRotateMaxBackups int
+	LogRotateCompress   bool
+	// Log sampling, disabled when LogSamplingInitial is 0
+	LogSamplingInitial    int
+	LogSamplingThereafter int
+	LogSamplingTick       time.Duration
+
+	// Error responses
+	ErrorFormat       string
+	ErrorTypeBaseURL  string
+	ErrorCaptureStack bool
+
+	// Conditional requests
+	ETagEnabled     bool
+	ETagWeak        bool
+	ETagMaxBodySize int
+
+	// Metrics
+	MetricsEnabled bool
+	MetricsPath    string
+	MetricsToken   string
+
+	// Tracing
+	TracingEnabled     bool
+	TracingExporter    string
+	TracingSampleRatio float64
+
 	// Firebase
 	FirebaseProjectID   string
 	FirebaseCredentials string
 
+	// Soft delete, the purge is disabled when SoftDeletePurgeInterval is 0
+	SoftDeleteRetention     time.Duration
+	SoftDeletePurgeInterval time.Duration
+
+	// Audit trail of repository changes
+	AuditEnabled    bool
+	AuditCollection string
+
 	// API Rate Limiting
 	RateLimitRequests int
 	RateLimitDuration time.Duration
@@ -34,6 +115,14 @@ type Config struct {
 
 	// Security
 	AuthTokenExpiry time.Duration
+
+	// Admin
+	AdminEnabled bool
+	AdminToken   string
+
+	// Modules
+	ModulesEnabled  []string
+	ModulesDisabled []string
 }
 
 func LoadConfig() *Config {
@@ -46,23 +135,112 @@ func LoadConfig() *Config {
 		Port:        getEnvAsInt("APP_PORT", 8080),
 		Debug:       getEnvAsBool("APP_DEBUG", true),
 
+		// HTTP Server
+		ServerListeners:         getEnvAsSlice("SERVER_LISTENERS", "tcp"),
+		ServerUnixSocket:        getEnv("SERVER_UNIX_SOCKET", "/run/golang-template/api.sock"),
+		ServerUnixSocketMode:    getEnvAsFileMode("SERVER_UNIX_SOCKET_MODE", 0o660),
+		ServerReadTimeout:       getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+		ServerReadHeaderTimeout: getEnvAsDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		ServerWriteTimeout:      getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+		ServerIdleTimeout:       getEnvAsDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+		ServerMaxHeaderBytes:    getEnvAsInt("SERVER_MAX_HEADER_BYTES", 1<<20),
+		ServerKeepAlives:        getEnvAsBool("SERVER_KEEP_ALIVES", true),
+		ServerH2C:               getEnvAsBool("SERVER_H2C", false),
+
+		// TLS
+		TLSCertFile:       getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:        getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:   getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:     getEnv("TLS_CLIENT_AUTH", "none"),
+		TLSReloadInterval: getEnvAsDuration("TLS_RELOAD_INTERVAL", 1*time.Minute),
+
+		// Graceful restart
+		GracefulRestart:        getEnvAsBool("GRACEFUL_RESTART", false),
+		GracefulRestartTimeout: getEnvAsDuration("GRACEFUL_RESTART_TIMEOUT", 30*time.Second),
+
+		// Shutdown
+		ShutdownTimeout:      getEnvAsDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		ShutdownPreStopDelay: getEnvAsDuration("SHUTDOWN_PRE_STOP_DELAY", 0),
+		ShutdownHookTimeout:  getEnvAsDuration("SHUTDOWN_HOOK_TIMEOUT", 5*time.Second),
+
+		// Logging
+		LogLevel:           getEnv("LOG_LEVEL", ""),
+		LogFormat:          getEnv("LOG_FORMAT", ""),
+		LogOutputPath:      getEnv("LOG_OUTPUT_PATH", ""),
+		LogSinks:           getEnv("LOG_SINKS", ""),
+		LogRedact:          getEnvAsBool("LOG_REDACT", true),
+		LogRedactKeys:      getEnvAsSlice("LOG_REDACT_KEYS", ""),
+		LogRedactDetectors: getEnvAsSlice("LOG_REDACT_DETECTORS", ""),
+		LogAccessHeaders:   getEnvAsSlice("LOG_ACCESS_HEADERS", "Referer,Content-Type"),
+		// Access log
+		LogAccessFields:        getEnvAsSlice("LOG_ACCESS_FIELDS", "method,path,route,status,latency,ip,user_agent,bytes_in,bytes_out,uid"),
+		LogAccessSkipPaths:     getEnvAsSlice("LOG_ACCESS_SKIP_PATHS", "/api/health/live,/api/health/ready,/metrics"),
+		LogAccessSlowThreshold: getEnvAsDuration("LOG_ACCESS_SLOW_THRESHOLD", time.Second),
+		LogAccessLevels:        getEnvAsSlice("LOG_ACCESS_LEVELS", "4xx=warn,5xx=error"),
+		// Log rotation
+		LogRotateMaxSizeMB:  getEnvAsInt("LOG_ROTATE_MAX_SIZE_MB", 0),
+		LogRotateInterval:   getEnvAsDuration("LOG_ROTATE_INTERVAL", 0),
+		LogRotateMaxAge:     getEnvAsDuration("LOG_ROTATE_MAX_AGE", 0),
+		LogRotateMaxBackups: getEnvAsInt("LOG_ROTATE_MAX_BACKUPS", 0),
+		LogRotateCompress:   getEnvAsBool("LOG_ROTATE_COMPRESS", false),
+		// Log sampling
+		LogSamplingInitial:    getEnvAsInt("LOG_SAMPLING_INITIAL", 0),
+		LogSamplingThereafter: getEnvAsInt("LOG_SAMPLING_THEREAFTER", 100),
+		LogSamplingTick:       getEnvAsDuration("LOG_SAMPLING_TICK", time.Second),
+
+		// Error responses
+		ErrorFormat:       getEnv("ERROR_FORMAT", "envelope"),
+		ErrorTypeBaseURL:  getEnv("ERROR_TYPE_BASE_URL", ""),
+		ErrorCaptureStack: getEnvAsBool("ERROR_CAPTURE_STACK", false),
+
+		// Conditional requests
+		ETagEnabled:     getEnvAsBool("ETAG_ENABLED", true),
+		ETagWeak:        getEnvAsBool("ETAG_WEAK", false),
+		ETagMaxBodySize: getEnvAsInt("ETAG_MAX_BODY_SIZE", 1<<20),
+
+		// Metrics
+		MetricsEnabled: getEnvAsBool("METRICS_ENABLED", false),
+		MetricsPath:    getEnv("METRICS_PATH", "/metrics"),
+		MetricsToken:   getEnv("METRICS_TOKEN", ""),
+
+		// Tracing
+		TracingEnabled:     getEnvAsBool("TRACING_ENABLED", false),
+		TracingExporter:    getEnv("TRACING_EXPORTER", "otlp"),
+		TracingSampleRatio: getEnvAsFloat("TRACING_SAMPLE_RATIO", 1),
+
 		// Firebase
 		FirebaseProjectID:   getEnv("FIREBASE_PROJECT_ID", ""),
 		FirebaseCredentials: getEnv("FIREBASE_SERVICE_ACCOUNT", "./credentials/firebase-service-account.json"),
 
+		// Soft delete
+		SoftDeleteRetention:     getEnvAsDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour),
+		SoftDeletePurgeInterval: getEnvAsDuration("SOFT_DELETE_PURGE_INTERVAL", 24*time.Hour),
+
+		// Audit trail
+		AuditEnabled:    getEnvAsBool("AUDIT_ENABLED", true),
+		AuditCollection: getEnv("AUDIT_COLLECTION", "audit_events"),
+
 		// API Rate Limiting
 		RateLimitRequests: getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
 		RateLimitDuration: getEnvAsDuration("RATE_LIMIT_DURATION", 1*time.Minute),
 
 		// CORS
 		CORSAllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", "*"),
-		CORSAllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS"),
-		CORSAllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", "Authorization,Content-Type,X-Requested-With"),
-		CORSExposedHeaders: getEnvAsSlice("CORS_EXPOSED_HEADERS", "Content-Length"),
+		CORSAllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS"),
+		CORSAllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", "Authorization,Content-Type,X-Requested-With,X-Request-ID,If-Match,If-None-Match,If-Modified-Since,If-Unmodified-Since"),
+		CORSExposedHeaders: getEnvAsSlice("CORS_EXPOSED_HEADERS", "Content-Length,X-Request-ID,ETag,Last-Modified"),
 		CORSMaxAge:         getEnvAsDuration("CORS_MAX_AGE", 12*time.Hour),
 
 		// Security
 		AuthTokenExpiry: getEnvAsDuration("AUTH_TOKEN_EXPIRY", 24*time.Hour),
+
+		// Admin
+		AdminEnabled: getEnvAsBool("ADMIN_ENABLED", false),
+		AdminToken:   getEnv("ADMIN_TOKEN", ""),
+
+		// Modules
+		ModulesEnabled:  getEnvAsSlice("MODULES_ENABLED", ""),
+		ModulesDisabled: getEnvAsSlice("MODULES_DISABLED", ""),
 	}
 }
 
@@ -112,6 +290,14 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	valueStr := getEnv(key, "")
 	if value, err := time.ParseDuration(valueStr); err == nil {
@@ -120,6 +306,14 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getEnvAsFileMode(key string, defaultValue os.FileMode) os.FileMode {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseUint(valueStr, 8, 32); err == nil {
+		return os.FileMode(value)
+	}
+	return defaultValue
+}
+
 func getEnvAsSlice(key string, defaultValue string) []string {
 	valueStr := getEnv(key, defaultValue)
 	if valueStr == "" {
@@ -0,0 +1,57 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secrets in Redacted
+const redactedValue = "[REDACTED]"
+
+// sensitiveFields are masked by Redacted when a field name ends with them,
+// so AdminToken is masked but AuthTokenExpiry is not
+var sensitiveFields = []string{"token", "secret", "password", "credentials", "apikey", "privatekey"}
+
+// Redacted returns the effective configuration keyed by field name with
+// secrets masked, durations and file modes are formatted as strings
+func (c *Config) Redacted() map[string]interface{} {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	out := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i).Interface()
+		switch {
+		case sensitiveField(field.Name):
+			if !v.Field(i).IsZero() {
+				value = redactedValue
+			}
+		case field.Type == reflect.TypeOf(time.Duration(0)):
+			value = value.(time.Duration).String()
+		case field.Type == reflect.TypeOf(os.FileMode(0)):
+			value = fmt.Sprintf("%#o", value)
+		}
+
+		out[field.Name] = value
+	}
+
+	return out
+}
+
+func sensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveFields {
+		if strings.HasSuffix(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
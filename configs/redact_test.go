@@ -0,0 +1,48 @@
+package configs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRedacted(t *testing.T) {
+	cfg := &Config{
+		AdminToken:           "admin-secret",
+		MetricsToken:         "",
+		AuthTokenExpiry:      time.Hour,
+		ServerUnixSocketMode: 0o660,
+		MetricsEnabled:       true,
+	}
+
+	got := cfg.Redacted()
+
+	tests := map[string]interface{}{
+		// Set secrets are masked, empty ones show they are unset
+		"AdminToken":   redactedValue,
+		"MetricsToken": "",
+		// Only names ending with a sensitive word are masked
+		"AuthTokenExpiry":      "1h0m0s",
+		"ServerUnixSocketMode": "0660",
+		"MetricsEnabled":       true,
+	}
+	for field, want := range tests {
+		if got[field] != want {
+			t.Errorf("Redacted()[%s] = %v, want %v", field, got[field], want)
+		}
+	}
+}
+
+func TestSensitiveField(t *testing.T) {
+	for name, want := range map[string]bool{
+		"AdminToken":              true,
+		"JWTSecret":               true,
+		"FirebaseCredentials":     true,
+		"StorageAPIKey":           true,
+		"AuthTokenExpiry":         false,
+		"FirebaseCredentialsPath": false,
+	} {
+		if got := sensitiveField(name); got != want {
+			t.Errorf("sensitiveField(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package firebase
+
+import (
+	"context"
+
+	"golang-template/pkg/common/audit"
+	apperrors "golang-template/pkg/common/errors"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+// Bounds of the page size of audit queries
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// AuditLog stores audit events in an append-only Firestore collection.
+// Events are only ever created, deny updates and deletes of the collection
+// in the Firestore security rules.
+type AuditLog struct {
+	client     *firestore.Client
+	collection string
+}
+
+var _ audit.Store = (*AuditLog)(nil)
+
+// NewAuditLog creates an audit log stored in a Firestore collection
+func NewAuditLog(client *firestore.Client, collection string) *AuditLog {
+	return &AuditLog{
+		client:     client,
+		collection: collection,
+	}
+}
+
+// Record appends an event, in the transaction of ctx if any so the event
+// is only stored with the change
+func (l *AuditLog) Record(ctx context.Context, event audit.Event) error {
+	ref := l.client.Collection(l.collection).NewDoc()
+	if tx := transactionFromContext(ctx); tx != nil {
+		return tx.Create(ref, event)
+	}
+	_, err := ref.Create(ctx, event)
+	return err
+}
+
+// Query returns the events matching the filter, newest first
+func (l *AuditLog) Query(ctx context.Context, filter audit.Filter) ([]audit.Event, string, error) {
+	limit := filter.Limit
+	if limit < 1 {
+		limit = defaultAuditLimit
+	}
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+
+	collection := l.client.Collection(l.collection)
+	query := collection.Query
+	if filter.Entity != "" {
+		query = query.Where("entity", "==", filter.Entity)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entityId", "==", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor", "==", filter.Actor)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("timestamp", ">=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("timestamp", "<", filter.To)
+	}
+	query = query.OrderBy("timestamp", firestore.Desc).
+		OrderBy(firestore.DocumentID, firestore.Desc)
+
+	if filter.Cursor != "" {
+		cursor, err := collection.Doc(filter.Cursor).Get(ctx)
+		if err != nil {
+			return nil, "", apperrors.Validation("cursor", "Unknown cursor").WithCause(err)
+		}
+		query = query.StartAfter(cursor)
+	}
+
+	// One more event tells whether there is a next page
+	docs := query.Limit(limit + 1).Documents(ctx)
+	defer docs.Stop()
+
+	events := make([]audit.Event, 0, limit)
+	for {
+		snap, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		if len(events) == limit {
+			return events, events[limit-1].ID, nil
+		}
+
+		var event audit.Event
+		if err := snap.DataTo(&event); err != nil {
+			return nil, "", err
+		}
+		event.ID = snap.Ref.ID
+		events = append(events, event)
+	}
+	return events, "", nil
+}
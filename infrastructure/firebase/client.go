@@ -8,12 +8,15 @@ import (
 
 	"golang-template/configs"
 	"golang-template/infrastructure/logger"
+	"golang-template/pkg/common/audit"
 
 	"cloud.google.com/go/firestore"
 	firebase "firebase.google.com/go"
 	"firebase.google.com/go/auth"
 	"firebase.google.com/go/storage"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc"
 )
 
 type Client struct {
@@ -23,6 +26,11 @@ type Client struct {
 	Storage   *storage.Client
 	Config    *configs.Config
 	Logger    logger.Logger
+	// Audit records the changes made by repositories, nil when disabled
+	Audit audit.Store
+
+	purgeMu sync.Mutex
+	purgers map[string]purger
 }
 
 var (
@@ -35,24 +43,26 @@ func Initialize(cfg *configs.Config, log logger.Logger) (*Client, error) {
 	var err error
 
 	once.Do(func() {
+		log = log.Named("firebase")
 		instance = &Client{
 			Config: cfg,
 			Logger: log,
 		}
 
-		var opt option.ClientOption
+		// Credentials shared by every service
+		var opts []option.ClientOption
 
 		// Check first for json format
 		if serviceAccountRaw := os.Getenv("FIREBASE_SERVICE_ACCOUNT"); serviceAccountRaw != "" {
 			// Try to parse as JSON directly
 			if strings.HasPrefix(strings.TrimSpace(serviceAccountRaw), "{") {
 				log.Info("Using Firebase credentials from environment variable parsed as JSON")
-				opt = option.WithCredentialsJSON([]byte(serviceAccountRaw))
+				opts = append(opts, option.WithCredentialsJSON([]byte(serviceAccountRaw)))
 			} else {
 				// Assume it's a file path
 				if _, err := os.Stat(cfg.GetFirebaseCredentialsPath()); err == nil {
 					log.Info("Using Firebase credentials from file", "path", cfg.GetFirebaseCredentialsPath())
-					opt = option.WithCredentialsFile(cfg.GetFirebaseCredentialsPath())
+					opts = append(opts, option.WithCredentialsFile(cfg.GetFirebaseCredentialsPath()))
 				} else {
 					log.Error("Firebase credentials file not found", "path", cfg.GetFirebaseCredentialsPath())
 				}
@@ -65,14 +75,29 @@ func Initialize(cfg *configs.Config, log logger.Logger) (*Client, error) {
 			ProjectID: cfg.FirebaseProjectID,
 		}
 
-		// Firebase app initialization
-		var app *firebase.App
-		if opt != nil {
-			app, err = firebase.NewApp(context.Background(), config, opt)
+		// The HTTP services of the app (Auth, Storage) share a client that
+		// forwards the request ID. Firestore uses gRPC, which rejects an
+		// HTTP client, so it is created separately below.
+		appOpts := opts
+		if httpClient, err := newHTTPClient(context.Background(), opts); err != nil {
+			log.Error("Failed to create Firebase HTTP client", "error", err)
 		} else {
-			app, err = firebase.NewApp(context.Background(), config)
+			appOpts = append(append([]option.ClientOption(nil), opts...), option.WithHTTPClient(httpClient))
+		}
+
+		// Firestore calls continue the trace found in their context and
+		// carry the request ID
+		firestoreOpts := append([]option.ClientOption{
+			option.WithGRPCDialOption(grpc.WithStatsHandler(otelgrpc.NewClientHandler())),
+		}, opts...)
+		for _, dialOpt := range requestIDDialOptions() {
+			firestoreOpts = append(firestoreOpts, option.WithGRPCDialOption(dialOpt))
 		}
 
+		// Firebase app initialization
+		var app *firebase.App
+		app, err = firebase.NewApp(context.Background(), config, appOpts...)
+
 		if err != nil {
 			log.Error("Failed to initialize Firebase app", "error", err)
 			return
@@ -87,9 +112,15 @@ func Initialize(cfg *configs.Config, log logger.Logger) (*Client, error) {
 		}
 
 		// Initialize Firestore
-		instance.Firestore, err = app.Firestore(context.Background())
+		projectID := cfg.FirebaseProjectID
+		if projectID == "" {
+			projectID = firestore.DetectProjectID
+		}
+		instance.Firestore, err = firestore.NewClient(context.Background(), projectID, firestoreOpts...)
 		if err != nil {
 			log.Error("Failed to initialize Firebase Firestore", "error", err)
+		} else if cfg.AuditEnabled {
+			instance.Audit = NewAuditLog(instance.Firestore, cfg.AuditCollection)
 		}
 
 		// Initialize Storage
@@ -0,0 +1,53 @@
+package firebase
+
+import (
+	"reflect"
+	"strings"
+
+	"cloud.google.com/go/firestore"
+)
+
+// fieldUpdates lists the top level fields of a struct as Firestore
+// updates, following the firestore tags and flattening embedded structs
+// like the Firestore encoder. Unlike Set, fields missing from the struct
+// are kept in the document.
+func fieldUpdates(v interface{}) []firestore.Update {
+	var updates []firestore.Update
+	appendFieldUpdates(&updates, reflect.Indirect(reflect.ValueOf(v)))
+	return updates
+}
+
+func appendFieldUpdates(updates *[]firestore.Update, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("firestore")
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+
+		value := v.Field(i)
+		if field.Anonymous && name == "" && value.Kind() == reflect.Pointer && value.IsNil() {
+			// A nil embedded struct has no fields to write.
+			continue
+		}
+		if field.Anonymous && name == "" && reflect.Indirect(value).Kind() == reflect.Struct {
+			appendFieldUpdates(updates, reflect.Indirect(value))
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(options, "omitempty") && value.IsZero() {
+			*updates = append(*updates, firestore.Update{Path: name, Value: firestore.Delete})
+			continue
+		}
+		*updates = append(*updates, firestore.Update{Path: name, Value: value.Interface()})
+	}
+}
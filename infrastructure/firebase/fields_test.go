@@ -0,0 +1,60 @@
+package firebase
+
+import (
+	"reflect"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+)
+
+type FieldsBase struct {
+	ID      string `firestore:"id"`
+	Version int64  `firestore:"version"`
+}
+
+type fieldsEntity struct {
+	*FieldsBase
+	Name     string `firestore:"name"`
+	Note     string `firestore:"note,omitempty"`
+	Internal string `firestore:"-"`
+	Plain    int
+	hidden   string
+}
+
+func TestFieldUpdates(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want []firestore.Update
+	}{
+		{
+			name: "nil embedded pointer is skipped",
+			in:   &fieldsEntity{Name: "a", Note: "n", Plain: 1},
+			want: []firestore.Update{
+				{Path: "name", Value: "a"},
+				{Path: "note", Value: "n"},
+				{Path: "Plain", Value: 1},
+			},
+		},
+		{
+			name: "embedded pointer is flattened",
+			in:   fieldsEntity{FieldsBase: &FieldsBase{ID: "x", Version: 2}, Name: "a"},
+			want: []firestore.Update{
+				{Path: "id", Value: "x"},
+				{Path: "version", Value: int64(2)},
+				{Path: "name", Value: "a"},
+				{Path: "note", Value: firestore.Delete},
+				{Path: "Plain", Value: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldUpdates(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fieldUpdates() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package firebase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"golang-template/pkg/common/audit"
+	"golang-template/pkg/common/principal"
+)
+
+// purger is a repository of soft deletable entities
+type purger interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+}
+
+// registerPurger adds the repository of a collection to the scheduled
+// purge, a later repository of the same collection replaces it
+func (c *Client) registerPurger(collection string, p purger) {
+	c.purgeMu.Lock()
+	defer c.purgeMu.Unlock()
+
+	if c.purgers == nil {
+		c.purgers = make(map[string]purger)
+	}
+	c.purgers[collection] = p
+}
+
+// PurgeDeleted permanently deletes the entities soft deleted before the
+// given time in every collection with a soft delete repository. A failing
+// collection does not stop the others, the first error is returned.
+func (c *Client) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	c.purgeMu.Lock()
+	collections := make([]string, 0, len(c.purgers))
+	for collection := range c.purgers {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+	purgers := make([]purger, len(collections))
+	for i, collection := range collections {
+		purgers[i] = c.purgers[collection]
+	}
+	c.purgeMu.Unlock()
+
+	total := 0
+	var firstErr error
+	for i, collection := range collections {
+		purged, err := purgers[i].PurgeDeleted(ctx, before)
+		total += purged
+		if err != nil {
+			c.Logger.Error("Purge of soft deleted entities failed", "collection", collection, "purged", purged, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if purged > 0 {
+			c.Logger.Info("Purged soft deleted entities", "collection", collection, "purged", purged)
+		}
+	}
+	return total, firstErr
+}
+
+// RunPurge purges the entities soft deleted for longer than retention on
+// start and then every interval, until ctx is done. The purge is audited
+// as audit.SystemActor.
+func (c *Client) RunPurge(ctx context.Context, interval, retention time.Duration) {
+	ctx = principal.NewContext(ctx, audit.SystemActor)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Errors are logged per collection, the next run retries
+		_, _ = c.PurgeDeleted(ctx, time.Now().Add(-retention))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package firebase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang-template/infrastructure/logger"
+)
+
+type fakePurger struct {
+	purged int
+	err    error
+	before time.Time
+}
+
+func (p *fakePurger) PurgeDeleted(_ context.Context, before time.Time) (int, error) {
+	p.before = before
+	return p.purged, p.err
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	errPurge := errors.New("purge failed")
+	failing := &fakePurger{purged: 1, err: errPurge}
+	users := &fakePurger{purged: 2}
+	orders := &fakePurger{purged: 3}
+
+	c := &Client{Logger: logger.Default()}
+	c.registerPurger("orders", &fakePurger{purged: 100})
+	c.registerPurger("orders", orders)
+	c.registerPurger("broken", failing)
+	c.registerPurger("users", users)
+
+	before := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	total, err := c.PurgeDeleted(context.Background(), before)
+
+	// A failing collection does not stop the others
+	if total != 6 {
+		t.Errorf("PurgeDeleted() = %d, want 6", total)
+	}
+	if !errors.Is(err, errPurge) {
+		t.Errorf("PurgeDeleted() error = %v, want %v", err, errPurge)
+	}
+	for _, p := range []*fakePurger{failing, users, orders} {
+		if !p.before.Equal(before) {
+			t.Errorf("purger called with %v, want %v", p.before, before)
+		}
+	}
+}
+
+func TestPurgeDeletedWithoutRepositories(t *testing.T) {
+	c := &Client{Logger: logger.Default()}
+	if total, err := c.PurgeDeleted(context.Background(), time.Now()); total != 0 || err != nil {
+		t.Errorf("PurgeDeleted() = %d, %v, want 0, nil", total, err)
+	}
+}
+
+func TestRunPurge(t *testing.T) {
+	p := &fakePurger{}
+	c := &Client{Logger: logger.Default()}
+	c.registerPurger("users", p)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	c.RunPurge(ctx, time.Hour, 24*time.Hour)
+
+	// The first purge runs on start, before ctx is checked
+	if want := start.Add(-24 * time.Hour); p.before.Before(want) || p.before.After(time.Now()) {
+		t.Errorf("purged before %v, want about %v", p.before, want)
+	}
+}
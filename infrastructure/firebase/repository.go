@@ -0,0 +1,589 @@
+package firebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"golang-template/app/core/entity"
+	"golang-template/app/core/interfaces"
+	"golang-template/infrastructure/logger"
+	"golang-template/pkg/common/audit"
+	"golang-template/pkg/common/conditional"
+	apperrors "golang-template/pkg/common/errors"
+	"golang-template/pkg/common/principal"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Repository is a Firestore implementation of interfaces.Repository storing
+// one entity per document of a collection, keyed by the entity ID. T must
+// be a pointer to a struct embedding entity.BaseEntity. When T also embeds
+// entity.SoftDelete the repository implements soft delete, see
+// interfaces.SoftDeleteRepository.
+type Repository[T entity.Entity] struct {
+	client     *firestore.Client
+	collection string
+	softDelete bool
+	audit      audit.Store
+}
+
+var _ interfaces.SoftDeleteRepository[*entity.BaseEntity] = (*Repository[*entity.BaseEntity])(nil)
+
+// NewRepository creates a repository for a Firestore collection. Changes
+// are recorded in the audit log of the client, if enabled. Soft delete
+// repositories are registered on the client for the scheduled purge.
+func NewRepository[T entity.Entity](client *Client, collection string) *Repository[T] {
+	_, softDelete := any(newEntity[T]()).(entity.SoftDeletable)
+
+	r := &Repository[T]{
+		client:     client.Firestore,
+		collection: collection,
+		softDelete: softDelete,
+		audit:      client.Audit,
+	}
+	if softDelete {
+		client.registerPurger(collection, r)
+	}
+	return r
+}
+
+func (r *Repository[T]) Create(ctx context.Context, e T) (T, error) {
+	ref := r.client.Collection(r.collection).NewDoc()
+	if e.GetID() != "" {
+		ref = r.client.Collection(r.collection).Doc(e.GetID())
+	}
+
+	now := time.Now().UTC()
+	e.SetID(ref.ID)
+	e.SetCreatedAt(now)
+	e.SetUpdatedAt(now)
+	if versioned, ok := any(e).(entity.Versioned); ok {
+		versioned.SetVersion(1)
+	}
+
+	var err error
+	if tx := transactionFromContext(ctx); tx != nil {
+		err = tx.Create(ref, e)
+	} else {
+		_, err = ref.Create(ctx, e)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	r.record(ctx, audit.ActionCreate, e.GetID(), nil, r.fields(e))
+	return e, nil
+}
+
+// Update writes an existing entity. It fails with NotFound when the
+// document does not exist, PRECONDITION_FAILED when the stored entity does
+// not match the If-Match or If-Unmodified-Since of the request and
+// CONFLICT when a versioned entity is stale or the document changes before
+// the write, which is conditioned on the update time that was read.
+func (r *Repository[T]) Update(ctx context.Context, e T) (T, error) {
+	var zero T
+	ref := r.client.Collection(r.collection).Doc(e.GetID())
+
+	snap, current, err := r.read(ctx, ref)
+	if err != nil {
+		return zero, err
+	}
+	if err := r.visible(ctx, snap); err != nil {
+		return zero, err
+	}
+	before := r.fields(current)
+
+	if err := conditional.FromContext(ctx).For(e.GetID()).Check(conditional.EntityTag(current), current.GetUpdatedAt()); err != nil {
+		return zero, err
+	}
+	if err := nextVersion(e, current); err != nil {
+		return zero, err
+	}
+
+	// Deleting and restoring go through Delete and Restore only
+	if deletable, ok := any(e).(entity.SoftDeletable); ok {
+		stored := any(current).(entity.SoftDeletable)
+		deletable.SetDeleted(stored.GetDeletedAt(), stored.GetDeletedBy())
+	}
+
+	e.SetCreatedAt(current.GetCreatedAt())
+	e.SetUpdatedAt(time.Now().UTC())
+
+	if err := r.write(ctx, ref, snap, e); err != nil {
+		return zero, err
+	}
+	r.record(ctx, audit.ActionUpdate, e.GetID(), before, r.fields(e))
+	return e, nil
+}
+
+// Delete removes an entity, or marks it deleted by the request principal
+// for soft deletable entities. With a request precondition the stored
+// entity is checked first.
+func (r *Repository[T]) Delete(ctx context.Context, id string) error {
+	if !r.softDelete {
+		return r.remove(ctx, id, audit.ActionDelete)
+	}
+
+	ref := r.client.Collection(r.collection).Doc(id)
+	snap, current, err := r.read(ctx, ref)
+	if err != nil {
+		return err
+	}
+	deletable := any(current).(entity.SoftDeletable)
+	if deletable.GetDeletedAt() != nil {
+		return apperrors.NotFound(r.collection, id)
+	}
+	if err := r.visible(ctx, snap); err != nil {
+		return err
+	}
+	if err := conditional.FromContext(ctx).For(id).Check(conditional.EntityTag(current), current.GetUpdatedAt()); err != nil {
+		return err
+	}
+
+	before := r.fields(current)
+	now := time.Now().UTC()
+	deletable.SetDeleted(&now, principal.FromContext(ctx))
+	touch(current, now)
+	if err := r.write(ctx, ref, snap, current); err != nil {
+		return err
+	}
+	r.record(ctx, audit.ActionDelete, id, before, r.fields(current))
+	return nil
+}
+
+// Restore undoes the soft delete of an entity, restoring a live entity
+// does nothing
+func (r *Repository[T]) Restore(ctx context.Context, id string) (T, error) {
+	var zero T
+	if !r.softDelete {
+		return zero, fmt.Errorf("firebase: %s entities are not soft deletable", r.collection)
+	}
+
+	ref := r.client.Collection(r.collection).Doc(id)
+	snap, current, err := r.read(ctx, ref)
+	if err != nil {
+		return zero, err
+	}
+	deletable := any(current).(entity.SoftDeletable)
+	if !softDeleted(snap) {
+		return current, nil
+	}
+	if err := conditional.FromContext(ctx).For(id).Check(conditional.EntityTag(current), current.GetUpdatedAt()); err != nil {
+		return zero, err
+	}
+
+	before := r.fields(current)
+	deletable.SetDeleted(nil, "")
+	touch(current, time.Now().UTC())
+	if err := r.write(ctx, ref, snap, current); err != nil {
+		return zero, err
+	}
+	r.record(ctx, audit.ActionRestore, id, before, r.fields(current))
+	return current, nil
+}
+
+// Purge removes an entity from Firestore, deleted or not. With a request
+// precondition the stored entity is checked first and the delete is
+// conditioned on its update time.
+func (r *Repository[T]) Purge(ctx context.Context, id string) error {
+	return r.remove(ctx, id, audit.ActionPurge)
+}
+
+// remove deletes a document, reading it first when there is a precondition
+// to check or a state to audit
+func (r *Repository[T]) remove(ctx context.Context, id, action string) error {
+	ref := r.client.Collection(r.collection).Doc(id)
+
+	precondition := firestore.Exists
+	var before map[string]interface{}
+	if p := conditional.FromContext(ctx).For(id); !p.Empty() || r.audit != nil {
+		snap, current, err := r.read(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if err := p.Check(conditional.EntityTag(current), current.GetUpdatedAt()); err != nil {
+			return err
+		}
+		precondition = firestore.LastUpdateTime(snap.UpdateTime)
+		before = r.fields(current)
+	}
+
+	var err error
+	if tx := transactionFromContext(ctx); tx != nil {
+		err = tx.Delete(ref, precondition)
+	} else {
+		_, err = ref.Delete(ctx, precondition)
+	}
+	if status.Code(err) == codes.FailedPrecondition {
+		return staleEntity(0, err)
+	}
+	if err != nil {
+		return err
+	}
+	r.record(ctx, action, id, before, nil)
+	return nil
+}
+
+// PurgeDeleted permanently deletes the entities soft deleted before the
+// given time. Entities restored while purging are kept.
+func (r *Repository[T]) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	if !r.softDelete {
+		return 0, nil
+	}
+
+	docs := r.client.Collection(r.collection).Where("deletedAt", "<", before).Documents(ctx)
+	defer docs.Stop()
+
+	writer := r.client.BulkWriter(ctx)
+	var jobs []*firestore.BulkWriterJob
+	var purgedDocs []*firestore.DocumentSnapshot
+	for {
+		snap, err := docs.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			writer.End()
+			return 0, err
+		}
+
+		job, err := writer.Delete(snap.Ref, firestore.LastUpdateTime(snap.UpdateTime))
+		if err != nil {
+			writer.End()
+			return 0, err
+		}
+		jobs = append(jobs, job)
+		purgedDocs = append(purgedDocs, snap)
+	}
+	writer.End()
+
+	purged := 0
+	var firstErr error
+	for i, job := range jobs {
+		_, err := job.Results()
+		switch {
+		case err == nil:
+			purged++
+			if r.audit != nil {
+				if e, err := decode[T](purgedDocs[i]); err == nil {
+					r.record(ctx, audit.ActionPurge, e.GetID(), r.fields(e), nil)
+				}
+			}
+		case status.Code(err) == codes.FailedPrecondition:
+			// Changed since the query, e.g. restored
+		case firstErr == nil:
+			firstErr = err
+		}
+	}
+	return purged, firstErr
+}
+
+func (r *Repository[T]) GetByID(ctx context.Context, id string) (T, error) {
+	snap, e, err := r.read(ctx, r.client.Collection(r.collection).Doc(id))
+	if err == nil {
+		err = r.visible(ctx, snap)
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return e, nil
+}
+
+// read gets and decodes a document, in the transaction of ctx if any
+func (r *Repository[T]) read(ctx context.Context, ref *firestore.DocumentRef) (*firestore.DocumentSnapshot, T, error) {
+	var snap *firestore.DocumentSnapshot
+	var err error
+	if tx := transactionFromContext(ctx); tx != nil {
+		snap, err = tx.Get(ref)
+	} else {
+		snap, err = ref.Get(ctx)
+	}
+	if err != nil {
+		var zero T
+		return nil, zero, err
+	}
+
+	e, err := decode[T](snap)
+	return snap, e, err
+}
+
+// visible returns NotFound for soft deleted entities unless ctx comes from
+// interfaces.WithDeleted, the same entities the query filter hides
+func (r *Repository[T]) visible(ctx context.Context, snap *firestore.DocumentSnapshot) error {
+	if !r.softDelete || interfaces.IncludesDeleted(ctx) {
+		return nil
+	}
+	if softDeleted(snap) {
+		return apperrors.NotFound(r.collection, snap.Ref.ID)
+	}
+	return nil
+}
+
+// softDeleted reports whether a document is hidden from reads: deleted, or
+// without a deletedAt field, which the deletedAt == null filter of queries
+// never matches
+func softDeleted(snap *firestore.DocumentSnapshot) bool {
+	deletedAt, err := snap.DataAt("deletedAt")
+	return err != nil || deletedAt != nil
+}
+
+// write updates the fields of e, failing with CONFLICT when the document
+// changed since snap was read
+func (r *Repository[T]) write(ctx context.Context, ref *firestore.DocumentRef, snap *firestore.DocumentSnapshot, e T) error {
+	updates := fieldUpdates(e)
+	unchanged := firestore.LastUpdateTime(snap.UpdateTime)
+
+	var err error
+	if tx := transactionFromContext(ctx); tx != nil {
+		err = tx.Update(ref, updates, unchanged)
+	} else {
+		_, err = ref.Update(ctx, updates, unchanged)
+	}
+	if status.Code(err) == codes.FailedPrecondition {
+		return staleEntity(0, err)
+	}
+	return err
+}
+
+// fields returns the audited state of an entity, nil when auditing is
+// disabled
+func (r *Repository[T]) fields(e T) map[string]interface{} {
+	if r.audit == nil {
+		return nil
+	}
+	fields, err := audit.Fields(e)
+	if err != nil {
+		return nil
+	}
+	return fields
+}
+
+// record appends an audit event for a change. In a transaction the event
+// is written with the change, otherwise after it and a failure is only
+// logged since the change is already stored.
+func (r *Repository[T]) record(ctx context.Context, action, id string, before, after map[string]interface{}) {
+	if r.audit == nil {
+		return
+	}
+
+	event := audit.NewEvent(ctx, action, r.collection, id, before, after)
+	if err := r.audit.Record(ctx, event); err != nil {
+		logger.FromContext(ctx).Error("Failed to record audit event",
+			"collection", r.collection, "id", id, "action", action, "error", err)
+	}
+}
+
+// touch records a change made by the repository itself
+func touch(e entity.Entity, now time.Time) {
+	e.SetUpdatedAt(now)
+	if versioned, ok := e.(entity.Versioned); ok {
+		versioned.SetVersion(versioned.GetVersion() + 1)
+	}
+}
+
+// nextVersion advances the version of a versioned entity, which must have
+// been read at the stored version of current
+func nextVersion(e, current entity.Entity) error {
+	versioned, ok := e.(entity.Versioned)
+	if !ok {
+		return nil
+	}
+
+	currentVersion := current.(entity.Versioned).GetVersion()
+	if versioned.GetVersion() != currentVersion {
+		return staleEntity(currentVersion, nil)
+	}
+	versioned.SetVersion(currentVersion + 1)
+	return nil
+}
+
+// staleEntity reports a write based on an outdated entity. The current
+// version is only known when the stale version was detected before writing.
+func staleEntity(currentVersion int64, cause error) error {
+	err := apperrors.FromCode(apperrors.CodeConflict, nil).WithCause(cause)
+	if currentVersion > 0 {
+		err = err.WithDetails(map[string]int64{"version": currentVersion})
+	}
+	return err
+}
+
+// List returns a page of entities, newest first, and the total matching
+// the filters
+func (r *Repository[T]) List(ctx context.Context, page, limit int, filters map[string]interface{}) ([]T, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	total, err := r.Count(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := r.query(ctx, filters).
+		OrderBy("createdAt", firestore.Desc).
+		Offset((page - 1) * limit).
+		Limit(limit)
+
+	it := &entityIterator[T]{docs: r.documents(ctx, query)}
+	defer it.Stop()
+
+	items := make([]T, 0, limit)
+	for {
+		item, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// Stream iterates over every entity matching the filters, reading the
+// documents from Firestore as they are consumed
+func (r *Repository[T]) Stream(ctx context.Context, filters map[string]interface{}) interfaces.Iterator[T] {
+	return &entityIterator[T]{docs: r.documents(ctx, r.query(ctx, filters))}
+}
+
+func (r *Repository[T]) Count(ctx context.Context, filters map[string]interface{}) (int64, error) {
+	query := r.query(ctx, filters)
+	aggregation := query.NewAggregationQuery().WithCount("count")
+	if tx := transactionFromContext(ctx); tx != nil {
+		aggregation = aggregation.Transaction(tx)
+	}
+
+	result, err := aggregation.Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count, ok := result["count"].(*firestorepb.Value)
+	if !ok {
+		return 0, errors.New("firestore: count aggregation without result")
+	}
+	return count.GetIntegerValue(), nil
+}
+
+func (r *Repository[T]) Exists(ctx context.Context, filters map[string]interface{}) (bool, error) {
+	docs := r.documents(ctx, r.query(ctx, filters).Limit(1))
+	defer docs.Stop()
+
+	_, err := docs.Next()
+	if err == iterator.Done {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Transaction runs fn in a Firestore transaction. Repository calls made
+// with the context passed to fn join the transaction; Firestore requires
+// all reads to happen before writes and may run fn more than once. Stale
+// writes fail with CONFLICT as outside transactions.
+func (r *Repository[T]) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if transactionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	var fnErr error
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		fnErr = fn(contextWithTransaction(ctx, tx))
+		return fnErr
+	})
+	// Writes are only sent on commit, a failed write precondition means a
+	// document changed since it was read in fn
+	if fnErr == nil && status.Code(err) == codes.FailedPrecondition {
+		return staleEntity(0, err)
+	}
+	return err
+}
+
+// query applies equality filters in key order so identical filters give
+// identical queries. Soft deleted entities are excluded unless ctx comes
+// from interfaces.WithDeleted or the filters select on deletedAt.
+func (r *Repository[T]) query(ctx context.Context, filters map[string]interface{}) firestore.Query {
+	query := r.client.Collection(r.collection).Query
+	if _, explicit := filters["deletedAt"]; r.softDelete && !explicit && !interfaces.IncludesDeleted(ctx) {
+		query = query.Where("deletedAt", "==", nil)
+	}
+
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		query = query.Where(field, "==", filters[field])
+	}
+	return query
+}
+
+func (r *Repository[T]) documents(ctx context.Context, query firestore.Query) *firestore.DocumentIterator {
+	if tx := transactionFromContext(ctx); tx != nil {
+		return tx.Documents(query)
+	}
+	return query.Documents(ctx)
+}
+
+// entityIterator decodes the documents of a query one at a time
+type entityIterator[T entity.Entity] struct {
+	docs *firestore.DocumentIterator
+}
+
+func (it *entityIterator[T]) Next() (T, error) {
+	snap, err := it.docs.Next()
+	if err != nil {
+		var zero T
+		if err == iterator.Done {
+			return zero, io.EOF
+		}
+		return zero, err
+	}
+	return decode[T](snap)
+}
+
+func (it *entityIterator[T]) Stop() {
+	it.docs.Stop()
+}
+
+func decode[T entity.Entity](snap *firestore.DocumentSnapshot) (T, error) {
+	e := newEntity[T]()
+	if err := snap.DataTo(e); err != nil {
+		var zero T
+		return zero, err
+	}
+	e.SetID(snap.Ref.ID)
+	return e, nil
+}
+
+// newEntity allocates the struct T points to
+func newEntity[T entity.Entity]() T {
+	var zero T
+	return reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+}
+
+type transactionKey struct{}
+
+func contextWithTransaction(ctx context.Context, tx *firestore.Transaction) context.Context {
+	return context.WithValue(ctx, transactionKey{}, tx)
+}
+
+func transactionFromContext(ctx context.Context) *firestore.Transaction {
+	tx, _ := ctx.Value(transactionKey{}).(*firestore.Transaction)
+	return tx
+}
@@ -0,0 +1,110 @@
+package firebase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang-template/app/core/entity"
+	apperrors "golang-template/pkg/common/errors"
+
+	"cloud.google.com/go/firestore"
+)
+
+type versionedEntity struct {
+	entity.BaseEntity
+	entity.SoftDelete
+	Name string `firestore:"name"`
+}
+
+type plainEntity struct {
+	ID        string
+	UpdatedAt time.Time
+}
+
+func (e *plainEntity) GetID() string            { return e.ID }
+func (e *plainEntity) SetID(id string)          { e.ID = id }
+func (e *plainEntity) GetCreatedAt() time.Time  { return time.Time{} }
+func (e *plainEntity) GetUpdatedAt() time.Time  { return e.UpdatedAt }
+func (e *plainEntity) SetCreatedAt(time.Time)   {}
+func (e *plainEntity) SetUpdatedAt(t time.Time) { e.UpdatedAt = t }
+
+func TestNextVersion(t *testing.T) {
+	current := &versionedEntity{BaseEntity: entity.BaseEntity{Version: 3}}
+
+	e := &versionedEntity{BaseEntity: entity.BaseEntity{Version: 3}}
+	if err := nextVersion(e, current); err != nil {
+		t.Fatalf("nextVersion() error = %v", err)
+	}
+	if e.Version != 4 {
+		t.Errorf("Version = %d, want 4", e.Version)
+	}
+
+	stale := &versionedEntity{BaseEntity: entity.BaseEntity{Version: 2}}
+	err := nextVersion(stale, current)
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.Code != apperrors.CodeConflict {
+		t.Fatalf("nextVersion() error = %v, want CONFLICT", err)
+	}
+	if details := appErr.Details.(map[string]int64); details["version"] != 3 {
+		t.Errorf("details = %v, want the current version", details)
+	}
+	if stale.Version != 2 {
+		t.Errorf("Version = %d, want a stale entity left unchanged", stale.Version)
+	}
+
+	if err := nextVersion(&plainEntity{}, &plainEntity{}); err != nil {
+		t.Errorf("nextVersion() of an unversioned entity error = %v", err)
+	}
+}
+
+func TestTouch(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	e := &versionedEntity{BaseEntity: entity.BaseEntity{Version: 1}}
+	touch(e, now)
+	if e.Version != 2 || !e.UpdatedAt.Equal(now) {
+		t.Errorf("touch() = version %d at %v, want 2 at %v", e.Version, e.UpdatedAt, now)
+	}
+
+	plain := &plainEntity{}
+	touch(plain, now)
+	if !plain.UpdatedAt.Equal(now) {
+		t.Errorf("touch() UpdatedAt = %v, want %v", plain.UpdatedAt, now)
+	}
+}
+
+func TestStaleEntity(t *testing.T) {
+	cause := errors.New("aborted")
+
+	err := staleEntity(0, cause)
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.Code != apperrors.CodeConflict || appErr.Details != nil {
+		t.Fatalf("staleEntity(0) = %#v, want CONFLICT without details", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("staleEntity() does not keep its cause")
+	}
+}
+
+func TestFieldUpdatesEntity(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+	e := &versionedEntity{
+		BaseEntity: entity.BaseEntity{ID: "a", CreatedAt: created, UpdatedAt: created, Version: 2},
+		Name:       "Jane",
+	}
+
+	want := []firestore.Update{
+		{Path: "id", Value: "a"},
+		{Path: "createdAt", Value: created},
+		{Path: "updatedAt", Value: created},
+		{Path: "version", Value: int64(2)},
+		{Path: "deletedAt", Value: (*time.Time)(nil)},
+		{Path: "deletedBy", Value: firestore.Delete},
+		{Path: "name", Value: "Jane"},
+	}
+	if got := fieldUpdates(e); !reflect.DeepEqual(got, want) {
+		t.Errorf("fieldUpdates() = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package firebase
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"golang-template/pkg/common/requestid"
+	"golang-template/pkg/httpclient"
+
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// firebaseScopes are the OAuth scopes the Firebase SDK requests for its
+// own HTTP clients
+var firebaseScopes = []string{
+	"https://www.googleapis.com/auth/cloud-platform",
+	"https://www.googleapis.com/auth/datastore",
+	"https://www.googleapis.com/auth/devstorage.full_control",
+	"https://www.googleapis.com/auth/firebase",
+	"https://www.googleapis.com/auth/identitytoolkit",
+	"https://www.googleapis.com/auth/userinfo.email",
+}
+
+// newHTTPClient creates the authenticated client of the HTTP services (Auth
+// and Storage). It forwards the request ID and trace context like the
+// clients built with pkg/httpclient.
+func newHTTPClient(ctx context.Context, opts []option.ClientOption) (*http.Client, error) {
+	opts = append(append([]option.ClientOption(nil), opts...), option.WithScopes(firebaseScopes...))
+	transport, err := htransport.NewTransport(ctx, httpclient.NewTransport(nil), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// requestIDDialOptions add the request ID of the call context to the
+// metadata of Firestore calls
+func requestIDDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(outgoingRequestID(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+		}),
+	}
+}
+
+func outgoingRequestID(ctx context.Context) context.Context {
+	id := requestid.FromContext(ctx)
+	if id == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, strings.ToLower(requestid.Header), id)
+}
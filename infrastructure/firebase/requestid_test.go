@@ -0,0 +1,77 @@
+package firebase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang-template/pkg/common/requestid"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNewHTTPClientForwardsRequestID(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		got = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "demo",
+		"private_key_id": "key",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"client_email":   "test@demo.iam.gserviceaccount.com",
+		"token_uri":      server.URL + "/token",
+	})
+
+	client, err := newHTTPClient(context.Background(), []option.ClientOption{option.WithCredentialsJSON(credentials)})
+	if err != nil {
+		t.Fatalf("newHTTPClient() error = %v", err)
+	}
+
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/accounts", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if id := got.Get(requestid.Header); id != "req-1" {
+		t.Errorf("%s = %q, want %q", requestid.Header, id, "req-1")
+	}
+	if auth := got.Get("Authorization"); auth != "Bearer token" {
+		t.Errorf("Authorization = %q, want %q", auth, "Bearer token")
+	}
+}
+
+func TestOutgoingRequestID(t *testing.T) {
+	ctx := outgoingRequestID(context.Background())
+	if _, ok := metadata.FromOutgoingContext(ctx); ok {
+		t.Error("metadata set without a request ID")
+	}
+
+	ctx = outgoingRequestID(requestid.NewContext(context.Background(), "req-1"))
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if got := md.Get("x-request-id"); len(got) != 1 || got[0] != "req-1" {
+		t.Errorf("x-request-id = %v, want [req-1]", got)
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type contextKey struct{}
+
+var (
+	defaultLogger Logger = &loggerImpl{sugar: zap.NewNop().Sugar(), zap: zap.NewNop(), levels: newLevels(zapcore.InfoLevel)}
+	defaultMu     sync.RWMutex
+)
+
+// SetDefault sets the logger returned by FromContext when the context
+// carries none
+func SetDefault(l Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	defaultLogger = l
+}
+
+// Default returns the logger set with SetDefault
+func Default() Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+
+	return defaultLogger
+}
+
+// WithContext returns a copy of ctx carrying the logger
+func WithContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, falling back to the default
+// logger. Request contexts carry a logger with request_id, trace_id, uid and
+// route fields.
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+			return l
+		}
+	}
+	return Default()
+}
+
+// WithFields returns a copy of ctx whose logger carries the extra fields
+func WithFields(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(keysAndValues...))
+}
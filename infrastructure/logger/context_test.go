@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newObservedLogger() (Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	z := zap.New(core)
+	return &loggerImpl{sugar: z.Sugar(), zap: z, levels: newLevels(zapcore.DebugLevel)}, logs
+}
+
+func TestFromContext(t *testing.T) {
+	//nolint:staticcheck // a nil context is accepted on purpose
+	if got := FromContext(nil); got != Default() {
+		t.Error("FromContext(nil) did not return the default logger")
+	}
+	if got := FromContext(context.Background()); got != Default() {
+		t.Error("FromContext() without a logger did not return the default logger")
+	}
+
+	l, _ := newObservedLogger()
+	if got := FromContext(WithContext(context.Background(), l)); got != l {
+		t.Error("FromContext() did not return the stored logger")
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	l, logs := newObservedLogger()
+
+	ctx := WithContext(context.Background(), l)
+	ctx = WithFields(ctx, "request_id", "req-1")
+	ctx = WithFields(ctx, "uid", "u1")
+	FromContext(ctx).Info("handled")
+
+	// The parent logger is not modified
+	l.Info("plain")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["request_id"] != "req-1" || fields["uid"] != "u1" {
+		t.Errorf("fields = %v, want request_id and uid", fields)
+	}
+	if len(entries[1].Context) != 0 {
+		t.Errorf("parent logger fields = %v, want none", entries[1].ContextMap())
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	previous := Default()
+	defer SetDefault(previous)
+
+	l, _ := newObservedLogger()
+	SetDefault(l)
+	if got := FromContext(context.Background()); got != l {
+		t.Error("FromContext() did not fall back to the logger set with SetDefault")
+	}
+}
@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Output formats accepted by LogConfig.Format
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+	// FormatGCP emits the structured fields Google Cloud Logging maps to
+	// severity, trace, span and source location
+	FormatGCP = "gcp"
+)
+
+// Special fields recognized by Cloud Logging
+const (
+	gcpTraceKey          = "logging.googleapis.com/trace"
+	gcpSpanKey           = "logging.googleapis.com/spanId"
+	gcpSourceLocationKey = "logging.googleapis.com/sourceLocation"
+)
+
+// Correlation field keys rewritten by the GCP profile
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// gcpEncoderConfig uses the field names Cloud Logging expects
+func gcpEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "severity",
+		NameKey:        "logger",
+		CallerKey:      zapcore.OmitKey,
+		FunctionKey:    zapcore.OmitKey,
+		MessageKey:     "message",
+		StacktraceKey:  "stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    gcpLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	}
+}
+
+// gcpLevelEncoder maps zap levels to Cloud Logging severities
+func gcpLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch level {
+	case zapcore.DebugLevel:
+		enc.AppendString("DEBUG")
+	case zapcore.InfoLevel:
+		enc.AppendString("INFO")
+	case zapcore.WarnLevel:
+		enc.AppendString("WARNING")
+	case zapcore.ErrorLevel:
+		enc.AppendString("ERROR")
+	case zapcore.DPanicLevel:
+		enc.AppendString("CRITICAL")
+	case zapcore.PanicLevel:
+		enc.AppendString("ALERT")
+	case zapcore.FatalLevel:
+		enc.AppendString("EMERGENCY")
+	default:
+		enc.AppendString("DEFAULT")
+	}
+}
+
+// gcpCore rewrites correlation fields and adds the source location
+type gcpCore struct {
+	zapcore.Core
+	projectID string
+}
+
+func newGCPCore(core zapcore.Core, projectID string) zapcore.Core {
+	return &gcpCore{Core: core, projectID: projectID}
+}
+
+func (c *gcpCore) With(fields []zapcore.Field) zapcore.Core {
+	return &gcpCore{
+		Core:      c.Core.With(c.rewrite(fields)),
+		projectID: c.projectID,
+	}
+}
+
+func (c *gcpCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *gcpCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	fields = c.rewrite(fields)
+
+	if entry.Caller.Defined {
+		fields = append(fields, zap.Object(gcpSourceLocationKey, sourceLocation{
+			file:     entry.Caller.File,
+			line:     entry.Caller.Line,
+			function: entry.Caller.Function,
+		}))
+	}
+
+	return c.Core.Write(entry, fields)
+}
+
+// rewrite turns trace_id and span_id into the Cloud Logging trace fields
+func (c *gcpCore) rewrite(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields), len(fields)+1)
+	copy(out, fields)
+
+	for i, field := range out {
+		if field.Type != zapcore.StringType {
+			continue
+		}
+
+		switch field.Key {
+		case TraceIDKey:
+			out[i].Key = gcpTraceKey
+			if c.projectID != "" {
+				out[i].String = "projects/" + c.projectID + "/traces/" + field.String
+			}
+		case SpanIDKey:
+			out[i].Key = gcpSpanKey
+		}
+	}
+
+	return out
+}
+
+type sourceLocation struct {
+	file     string
+	line     int
+	function string
+}
+
+func (s sourceLocation) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("file", s.file)
+	enc.AddString("line", strconv.Itoa(s.line))
+	enc.AddString("function", s.function)
+	return nil
+}
+
+// HTTPRequest is logged as the Cloud Logging httpRequest object
+type HTTPRequest struct {
+	RequestMethod string
+	RequestURL    string
+	RequestSize   int64
+	Status        int
+	ResponseSize  int64
+	UserAgent     string
+	RemoteIP      string
+	Referer       string
+	Latency       time.Duration
+	Protocol      string
+}
+
+func (r HTTPRequest) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("requestMethod", r.RequestMethod)
+	enc.AddString("requestUrl", r.RequestURL)
+	if r.RequestSize > 0 {
+		enc.AddString("requestSize", strconv.FormatInt(r.RequestSize, 10))
+	}
+	enc.AddInt("status", r.Status)
+	if r.ResponseSize >= 0 {
+		enc.AddString("responseSize", strconv.FormatInt(r.ResponseSize, 10))
+	}
+	if r.UserAgent != "" {
+		enc.AddString("userAgent", r.UserAgent)
+	}
+	if r.RemoteIP != "" {
+		enc.AddString("remoteIp", r.RemoteIP)
+	}
+	if r.Referer != "" {
+		enc.AddString("referer", r.Referer)
+	}
+	enc.AddString("latency", strconv.FormatFloat(r.Latency.Seconds(), 'f', 9, 64)+"s")
+	if r.Protocol != "" {
+		enc.AddString("protocol", r.Protocol)
+	}
+	return nil
+}
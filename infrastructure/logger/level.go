@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels holds the root log level and per-named-logger overrides, all of
+// which can be changed at runtime
+type Levels struct {
+	mu      sync.RWMutex
+	root    zap.AtomicLevel
+	named   map[string]zapcore.Level
+	reverts map[string]*levelRevert
+}
+
+// levelRevert restores a level once its TTL expires
+type levelRevert struct {
+	timer    *time.Timer
+	at       time.Time
+	previous *zapcore.Level
+}
+
+// LevelState describes a level override
+type LevelState struct {
+	Level    string     `json:"level"`
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+}
+
+// RootLogger is the name used for the root level
+const RootLogger = ""
+
+func newLevels(root zapcore.Level) *Levels {
+	return &Levels{
+		root:    zap.NewAtomicLevelAt(root),
+		named:   make(map[string]zapcore.Level),
+		reverts: make(map[string]*levelRevert),
+	}
+}
+
+// ParseLevel converts a level name such as "debug" or "warn"
+func ParseLevel(level string) (zapcore.Level, error) {
+	var l zapcore.Level
+	normalized := strings.ToLower(strings.TrimSpace(level))
+	// zap reads an empty level as info
+	if normalized == "" {
+		return l, fmt.Errorf("invalid log level %q", level)
+	}
+	if err := l.UnmarshalText([]byte(normalized)); err != nil {
+		return l, fmt.Errorf("invalid log level %q", level)
+	}
+	return l, nil
+}
+
+// Root returns the root level
+func (l *Levels) Root() zapcore.Level {
+	return l.root.Level()
+}
+
+// Level returns the effective level of a named logger: its own override,
+// the closest parent override ("a" for "a.b") or the root level
+func (l *Levels) Level(name string) zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for name != "" {
+		if level, ok := l.named[name]; ok {
+			return level
+		}
+
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	return l.root.Level()
+}
+
+// minLevel returns the most verbose level currently configured
+func (l *Levels) minLevel() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	min := l.root.Level()
+	for _, level := range l.named {
+		if level < min {
+			min = level
+		}
+	}
+	return min
+}
+
+// Set changes the level of the root (name "") or a named logger. A
+// positive ttl reverts the change once it expires.
+func (l *Levels) Set(name string, level zapcore.Level, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous := l.currentLocked(name)
+	if existing, ok := l.reverts[name]; ok {
+		// Keep reverting to the level set before the first temporary change
+		existing.timer.Stop()
+		previous = existing.previous
+		delete(l.reverts, name)
+	}
+
+	l.setLocked(name, &level)
+
+	if ttl > 0 {
+		revert := &levelRevert{at: time.Now().Add(ttl), previous: previous}
+		revert.timer = time.AfterFunc(ttl, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			if l.reverts[name] == revert {
+				l.setLocked(name, revert.previous)
+				delete(l.reverts, name)
+			}
+		})
+		l.reverts[name] = revert
+	}
+}
+
+// Reset removes the override of a named logger so it follows its parent
+func (l *Levels) Reset(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if existing, ok := l.reverts[name]; ok {
+		existing.timer.Stop()
+		delete(l.reverts, name)
+	}
+
+	if name != RootLogger {
+		delete(l.named, name)
+	}
+}
+
+// States returns the root level and every named override
+func (l *Levels) States() (LevelState, map[string]LevelState) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	root := l.stateLocked(RootLogger, l.root.Level())
+
+	names := make([]string, 0, len(l.named))
+	for name := range l.named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	named := make(map[string]LevelState, len(names))
+	for _, name := range names {
+		named[name] = l.stateLocked(name, l.named[name])
+	}
+
+	return root, named
+}
+
+func (l *Levels) stateLocked(name string, level zapcore.Level) LevelState {
+	state := LevelState{Level: level.String()}
+	if revert, ok := l.reverts[name]; ok {
+		at := revert.at
+		state.RevertAt = &at
+	}
+	return state
+}
+
+func (l *Levels) currentLocked(name string) *zapcore.Level {
+	if name == RootLogger {
+		level := l.root.Level()
+		return &level
+	}
+	if level, ok := l.named[name]; ok {
+		return &level
+	}
+	return nil
+}
+
+// setLocked sets a level, nil removes a named override
+func (l *Levels) setLocked(name string, level *zapcore.Level) {
+	if name == RootLogger {
+		if level != nil {
+			l.root.SetLevel(*level)
+		}
+		return
+	}
+
+	if level == nil {
+		delete(l.named, name)
+		return
+	}
+	l.named[name] = *level
+}
+
+// levelCore filters entries by the effective level of their logger name
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func newLevelCore(core zapcore.Core, levels *Levels) zapcore.Core {
+	return &levelCore{Core: core, levels: levels}
+}
+
+// Enabled is used as a fast path before the logger name is known, so it
+// accepts anything a named override could enable
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= c.levels.minLevel()
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < c.levels.Level(entry.LoggerName) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
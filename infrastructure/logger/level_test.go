@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    zapcore.Level
+		wantErr bool
+	}{
+		{in: "debug", want: zapcore.DebugLevel},
+		{in: " WARN ", want: zapcore.WarnLevel},
+		{in: "error", want: zapcore.ErrorLevel},
+		{in: "", wantErr: true},
+		{in: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLevelsInheritance(t *testing.T) {
+	levels := newLevels(zapcore.InfoLevel)
+	levels.Set("firebase", zapcore.DebugLevel, 0)
+
+	tests := map[string]zapcore.Level{
+		"":               zapcore.InfoLevel,
+		"access":         zapcore.InfoLevel,
+		"firebase":       zapcore.DebugLevel,
+		"firebase.audit": zapcore.DebugLevel,
+	}
+	for name, want := range tests {
+		if got := levels.Level(name); got != want {
+			t.Errorf("Level(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if got := levels.minLevel(); got != zapcore.DebugLevel {
+		t.Errorf("minLevel() = %v, want debug", got)
+	}
+
+	levels.Reset("firebase")
+	if got := levels.Level("firebase.audit"); got != zapcore.InfoLevel {
+		t.Errorf("Level after Reset = %v, want info", got)
+	}
+}
+
+func TestLevelsRevert(t *testing.T) {
+	levels := newLevels(zapcore.InfoLevel)
+
+	levels.Set(RootLogger, zapcore.DebugLevel, 20*time.Millisecond)
+	// A second temporary change keeps reverting to the original level
+	levels.Set(RootLogger, zapcore.WarnLevel, 20*time.Millisecond)
+
+	root, _ := levels.States()
+	if root.Level != "warn" || root.RevertAt == nil {
+		t.Fatalf("States() root = %+v, want warn with a revert time", root)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for levels.Root() != zapcore.InfoLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("Root() = %v, want info after the ttl", levels.Root())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	levels.Set("access", zapcore.ErrorLevel, 20*time.Millisecond)
+	time.Sleep(60 * time.Millisecond)
+	if _, named := levels.States(); len(named) != 0 {
+		t.Errorf("States() named = %v, want the temporary override removed", named)
+	}
+}
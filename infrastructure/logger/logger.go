@@ -3,6 +3,8 @@ package logger
 import (
 	"os"
 
+	"golang-template/configs"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -10,14 +12,27 @@ import (
 type LogConfig struct {
 	// Environment: "development", "production", etc.
 	Environment string
-	// Debug enables/disables debug-level logging
+	// Debug enables debug-level logging when Level is not set
 	Debug bool
 	// JSON enables JSON formatting (typically used in production)
 	JSON bool
-	// Level sets the minimum log level
+	// Format selects the output profile: console, json or gcp. When empty
+	// it follows JSON.
+	Format string
+	// GCPProjectID qualifies trace IDs in the gcp format
+	GCPProjectID string
+	// Level sets the minimum log level, it takes precedence over Debug
 	Level string
-	// Output file path (empty for stdout)
+	// Output file path (empty for stdout), ignored when Sinks are set
 	OutputPath string
+	// Sinks replace stdout and OutputPath with independent destinations
+	Sinks []SinkConfig
+	// Rotation applies to file sinks
+	Rotation RotationConfig
+	// Sampling limits repeated entries
+	Sampling SamplingConfig
+	// Redact masks sensitive fields, enabled with defaults unless disabled
+	Redact RedactConfig
 }
 
 // Logger interface for application logging
@@ -28,25 +43,32 @@ type Logger interface {
 	Error(msg string, keysAndValues ...interface{})
 	Fatal(msg string, keysAndValues ...interface{})
 	With(keysAndValues ...interface{}) Logger
+	Named(name string) Logger
+	Levels() *Levels
 	ZapLogger() *zap.Logger
 }
 
 // loggerImpl implements the Logger interface with zap
 type loggerImpl struct {
-	sugar *zap.SugaredLogger
-	zap   *zap.Logger
+	sugar  *zap.SugaredLogger
+	zap    *zap.Logger
+	levels *Levels
 }
 
-const (
-	defaultLogLevel = "info"
-)
-
+// NewLogger creates a console logger, JSON in production, with the level
+// of LOG_LEVEL. It falls back to a development logger that reports the
+// error when the configuration is invalid, use NewLoggerE to handle it.
 func NewLogger(debug bool) Logger {
+	return orFallback(NewLoggerE(debug))
+}
+
+// NewLoggerE is NewLogger returning an invalid configuration as an error
+func NewLoggerE(debug bool) (Logger, error) {
 	config := LogConfig{
 		Environment: "development",
 		Debug:       debug,
 		JSON:        false,
-		Level:       defaultLogLevel,
+		Level:       os.Getenv("LOG_LEVEL"),
 	}
 
 	// In production, use JSON format
@@ -55,86 +77,174 @@ func NewLogger(debug bool) Logger {
 		config.JSON = true
 	}
 
-	return NewLoggerWithConfig(config)
+	return NewLoggerWithConfigE(config)
 }
 
-// NewLoggerWithConfig creates a logger with specific configuration
-func NewLoggerWithConfig(config LogConfig) Logger {
-	var level zapcore.Level
-	switch config.Level {
-	case "debug":
-		level = zapcore.DebugLevel
-	case "info":
-		level = zapcore.InfoLevel
-	case "warn":
-		level = zapcore.WarnLevel
-	case "error":
-		level = zapcore.ErrorLevel
-	default:
-		level = zapcore.InfoLevel
+// NewLoggerFromConfig creates a logger from the application configuration.
+// It falls back like NewLogger, use NewLoggerFromConfigE to fail instead.
+func NewLoggerFromConfig(cfg *configs.Config) Logger {
+	return orFallback(NewLoggerFromConfigE(cfg))
+}
+
+// NewLoggerFromConfigE creates a logger from the application
+// configuration, an invalid level, format or sink is an error so startup
+// fails instead of logging somewhere unexpected
+func NewLoggerFromConfigE(cfg *configs.Config) (Logger, error) {
+	projectID := cfg.FirebaseProjectID
+	if projectID == "" {
+		projectID = os.Getenv("GOOGLE_CLOUD_PROJECT")
 	}
 
-	// Override level if debug mode is enabled
+	sinks, err := ParseSinks(cfg.LogSinks)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLoggerWithConfigE(LogConfig{
+		Environment:  cfg.Environment,
+		Debug:        cfg.Debug,
+		JSON:         cfg.Environment == "production",
+		Format:       cfg.LogFormat,
+		GCPProjectID: projectID,
+		Level:        cfg.LogLevel,
+		OutputPath:   cfg.LogOutputPath,
+		Sinks:        sinks,
+		Rotation: RotationConfig{
+			MaxSizeMB:  cfg.LogRotateMaxSizeMB,
+			Interval:   cfg.LogRotateInterval,
+			MaxAge:     cfg.LogRotateMaxAge,
+			MaxBackups: cfg.LogRotateMaxBackups,
+			Compress:   cfg.LogRotateCompress,
+		},
+		Sampling: SamplingConfig{
+			Initial:    cfg.LogSamplingInitial,
+			Thereafter: cfg.LogSamplingThereafter,
+			Tick:       cfg.LogSamplingTick,
+		},
+		Redact: RedactConfig{
+			Disabled:  !cfg.LogRedact,
+			Keys:      cfg.LogRedactKeys,
+			Detectors: cfg.LogRedactDetectors,
+		},
+	})
+}
+
+// NewLoggerWithConfig creates a logger with specific configuration. It
+// falls back like NewLogger, use NewLoggerWithConfigE to fail instead.
+func NewLoggerWithConfig(config LogConfig) Logger {
+	return orFallback(NewLoggerWithConfigE(config))
+}
+
+// NewLoggerWithConfigE creates a logger with specific configuration and
+// returns an invalid configuration as an error
+func NewLoggerWithConfigE(config LogConfig) (Logger, error) {
+	level := zapcore.InfoLevel
 	if config.Debug {
 		level = zapcore.DebugLevel
 	}
 
-	// Create encoder config
-	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "timestamp",
-		LevelKey:       "level",
-		NameKey:        "logger",
-		CallerKey:      "caller",
-		FunctionKey:    zapcore.OmitKey,
-		MessageKey:     "message",
-		StacktraceKey:  "stacktrace",
-		LineEnding:     zapcore.DefaultLineEnding,
-		EncodeLevel:    zapcore.CapitalLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.StringDurationEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
+	// An explicit level overrides debug mode
+	if config.Level != "" {
+		parsed, err := ParseLevel(config.Level)
+		if err != nil {
+			return nil, err
+		}
+		level = parsed
 	}
 
-	// In development mode, use colorful output for console
-	if config.Environment == "development" && !config.JSON {
-		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-	}
+	levels := newLevels(level)
 
-	// Define output paths
-	outputPaths := []string{"stdout"}
-	if config.OutputPath != "" {
-		outputPaths = append(outputPaths, config.OutputPath)
+	redactor, err := newRedactor(config.Redact)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create zap configuration
-	zapConfig := zap.Config{
-		Level:            zap.NewAtomicLevelAt(level),
-		Development:      config.Environment == "development",
-		Encoding:         "console", // Default for development
-		EncoderConfig:    encoderConfig,
-		OutputPaths:      outputPaths,
-		ErrorOutputPaths: []string{"stderr"},
+	// Define sinks, stdout plus the optional output file by default
+	sinks := config.Sinks
+	if len(sinks) == 0 {
+		sinks = []SinkConfig{{Output: OutputStdout}}
+		if config.OutputPath != "" {
+			sinks = append(sinks, SinkConfig{Output: config.OutputPath})
+		}
 	}
 
-	// Use JSON encoding for production
-	if config.JSON {
-		zapConfig.Encoding = "json"
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, sink := range sinks {
+		core, err := newSinkCore(sink, config, redactor)
+		if err != nil {
+			return nil, err
+		}
+		cores = append(cores, core)
 	}
 
-	// Build the logger
-	zapLogger, err := zapConfig.Build(zap.AddCallerSkip(1))
+	// Levels are enforced by the level core so they can change at runtime,
+	// sampling only counts entries that pass them
+	core := newLevelCore(newSamplerCore(zapcore.NewTee(cores...), config.Sampling), levels)
+
+	errorOutput, _, err := zap.Open("stderr")
 	if err != nil {
-		fallback := zap.NewExample()
-		fallback.Error("Failed to create logger", zap.Error(err))
-		return &loggerImpl{
-			sugar: fallback.Sugar(),
-			zap:   fallback,
-		}
+		return nil, err
 	}
 
+	stackLevel := zapcore.ErrorLevel
+	options := []zap.Option{zap.ErrorOutput(errorOutput), zap.AddCaller(), zap.AddCallerSkip(1)}
+	if config.Environment == "development" {
+		stackLevel = zapcore.WarnLevel
+		options = append(options, zap.Development())
+	}
+	options = append(options, zap.AddStacktrace(stackLevel))
+
+	// Build the logger
+	zapLogger := zap.New(core, options...)
+
+	return &loggerImpl{
+		sugar:  zapLogger.Sugar(),
+		zap:    zapLogger,
+		levels: levels,
+	}, nil
+}
+
+// orFallback returns a development logger reporting err when the
+// configuration cannot be applied
+func orFallback(l Logger, err error) Logger {
+	if err == nil {
+		return l
+	}
+	fallback := zap.NewExample()
+	fallback.Error("Failed to create logger", zap.Error(err))
 	return &loggerImpl{
-		sugar: zapLogger.Sugar(),
-		zap:   zapLogger,
+		sugar:  fallback.Sugar(),
+		zap:    fallback,
+		levels: newLevels(zapcore.DebugLevel),
+	}
+}
+
+// format returns the default output format of the sinks
+func (c LogConfig) format() string {
+	if c.Format != "" {
+		return c.Format
+	}
+	if c.JSON {
+		return FormatJSON
+	}
+	return FormatConsole
+}
+
+// defaultEncoderConfig is used by the console and json formats
+func defaultEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		FunctionKey:    zapcore.OmitKey,
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 }
 
@@ -166,11 +276,27 @@ func (l *loggerImpl) Fatal(msg string, keysAndValues ...interface{}) {
 // With creates a child logger with the provided context fields
 func (l *loggerImpl) With(keysAndValues ...interface{}) Logger {
 	return &loggerImpl{
-		sugar: l.sugar.With(keysAndValues...),
-		zap:   l.zap,
+		sugar:  l.sugar.With(keysAndValues...),
+		zap:    l.zap,
+		levels: l.levels,
+	}
+}
+
+// Named creates a child logger whose level can be changed independently
+// through Levels
+func (l *loggerImpl) Named(name string) Logger {
+	return &loggerImpl{
+		sugar:  l.sugar.Named(name),
+		zap:    l.zap.Named(name),
+		levels: l.levels,
 	}
 }
 
+// Levels provides runtime control over the log levels
+func (l *loggerImpl) Levels() *Levels {
+	return l.levels
+}
+
 // ZapLogger provides direct access to the underlying zap logger
 func (l *loggerImpl) ZapLogger() *zap.Logger {
 	return l.zap
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"testing"
+)
+
+func TestNewLoggerWithConfigE(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  LogConfig
+		wantErr bool
+	}{
+		{name: "defaults", config: LogConfig{}},
+		{name: "level", config: LogConfig{Level: "warn", Format: FormatJSON}},
+		{name: "invalid level", config: LogConfig{Level: "verbose"}, wantErr: true},
+		{name: "invalid format", config: LogConfig{Format: "jsonn"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := NewLoggerWithConfigE(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewLoggerWithConfigE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && l == nil {
+				t.Fatal("NewLoggerWithConfigE() returned a nil logger")
+			}
+
+			// The variant without error never returns nil
+			if l := NewLoggerWithConfig(tt.config); l == nil || l.Levels() == nil {
+				t.Fatal("NewLoggerWithConfig() returned an unusable logger")
+			}
+		})
+	}
+}
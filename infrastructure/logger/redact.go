@@ -0,0 +1,351 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted replaces sensitive values in log output
+const Redacted = "[REDACTED]"
+
+// Value detectors accepted by RedactConfig.Detectors
+const (
+	DetectorJWT    = "jwt"
+	DetectorAPIKey = "apikey"
+	DetectorEmail  = "email"
+	DetectorCard   = "card"
+)
+
+// RedactConfig configures which structured fields are masked
+type RedactConfig struct {
+	// Disabled turns redaction off
+	Disabled bool
+	// Keys are case-insensitive patterns; a field whose key contains one of
+	// them is fully masked. '-' and '_' are ignored when matching.
+	Keys []string
+	// Detectors mask matching substrings in any string value
+	Detectors []string
+}
+
+// DefaultRedactKeys are masked when RedactConfig.Keys is empty
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie",
+	"apikey", "accesskey", "privatekey", "credential", "session",
+}
+
+// DefaultRedactDetectors are used when RedactConfig.Detectors is empty
+var DefaultRedactDetectors = []string{DetectorJWT, DetectorAPIKey, DetectorEmail, DetectorCard}
+
+var detectorPatterns = map[string]*regexp.Regexp{
+	DetectorJWT:    regexp.MustCompile(`eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*`),
+	DetectorAPIKey: regexp.MustCompile(`\b(?:AIza[0-9A-Za-z_-]{35}|AKIA[0-9A-Z]{16}|sk_(?:live|test)_[0-9A-Za-z]{16,}|sk-[A-Za-z0-9_-]{20,}|gh[pousr]_[A-Za-z0-9]{36,}|xox[baprs]-[A-Za-z0-9-]{10,})\b`),
+	DetectorEmail:  regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	DetectorCard:   regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+}
+
+// redactor masks sensitive keys and values
+type redactor struct {
+	keys      []string
+	detectors []*regexp.Regexp
+	card      bool
+	pairs     *regexp.Regexp
+	headers   *regexp.Regexp
+}
+
+func newRedactor(config RedactConfig) (*redactor, error) {
+	if config.Disabled {
+		return nil, nil
+	}
+
+	keys := config.Keys
+	if len(keys) == 0 {
+		keys = DefaultRedactKeys
+	}
+
+	detectors := config.Detectors
+	if len(detectors) == 0 {
+		detectors = DefaultRedactDetectors
+	}
+
+	r := &redactor{}
+
+	quoted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		key = normalizeKey(key)
+		if key == "" {
+			continue
+		}
+		r.keys = append(r.keys, key)
+		quoted = append(quoted, keyPattern(key))
+	}
+
+	for _, name := range detectors {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || name == "none" {
+			continue
+		}
+
+		pattern, ok := detectorPatterns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction detector %q", name)
+		}
+
+		if name == DetectorCard {
+			r.card = true
+			continue
+		}
+		r.detectors = append(r.detectors, pattern)
+	}
+
+	// Sensitive pairs embedded in strings, e.g. query strings and dumped headers
+	if len(quoted) > 0 {
+		alternation := strings.Join(quoted, "|")
+		r.pairs = regexp.MustCompile(`(?i)([\w-]*(?:` + alternation + `)[\w-]*=)[^&\s]+`)
+		r.headers = regexp.MustCompile(`(?im)^([\w-]*(?:` + alternation + `)[\w-]*:[ \t]*)[^\r\n]+`)
+	}
+
+	return r, nil
+}
+
+// keyPattern matches a normalized key with optional '-' or '_' separators,
+// so "apikey" also matches "api_key" and "Api-Key"
+func keyPattern(key string) string {
+	parts := make([]string, 0, len(key))
+	for _, r := range key {
+		parts = append(parts, regexp.QuoteMeta(string(r)))
+	}
+	return strings.Join(parts, `[-_]?`)
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	key = strings.ReplaceAll(key, "_", "")
+	return strings.ReplaceAll(key, "-", "")
+}
+
+// sensitiveKey reports whether a field key matches a configured pattern
+func (r *redactor) sensitiveKey(key string) bool {
+	key = normalizeKey(key)
+	for _, pattern := range r.keys {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString masks detected values inside s
+func (r *redactor) redactString(s string) string {
+	if r.pairs != nil {
+		s = r.pairs.ReplaceAllString(s, "${1}"+Redacted)
+		s = r.headers.ReplaceAllString(s, "${1}"+Redacted)
+	}
+
+	for _, pattern := range r.detectors {
+		s = pattern.ReplaceAllString(s, Redacted)
+	}
+
+	if r.card {
+		s = detectorPatterns[DetectorCard].ReplaceAllStringFunc(s, func(match string) string {
+			if luhnValid(match) {
+				return Redacted
+			}
+			return match
+		})
+	}
+
+	return s
+}
+
+// redactValue masks values nested in the maps and slices commonly logged
+// through the sugared logger
+func (r *redactor) redactValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		return r.redactString(v), true
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for key, item := range v {
+			if r.sensitiveKey(key) {
+				out[key] = Redacted
+			} else {
+				out[key] = r.redactString(item)
+			}
+		}
+		return out, true
+	case map[string][]string:
+		out := make(map[string][]string, len(v))
+		for key, items := range v {
+			masked := make([]string, len(items))
+			for i, item := range items {
+				if r.sensitiveKey(key) {
+					masked[i] = Redacted
+				} else {
+					masked[i] = r.redactString(item)
+				}
+			}
+			out[key] = masked
+		}
+		return out, true
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if r.sensitiveKey(key) {
+				out[key] = Redacted
+			} else if masked, ok := r.redactValue(item); ok {
+				out[key] = masked
+			} else {
+				out[key] = item
+			}
+		}
+		return out, true
+	case []string:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = r.redactString(item)
+		}
+		return out, true
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			if masked, ok := r.redactValue(item); ok {
+				out[i] = masked
+			} else {
+				out[i] = item
+			}
+		}
+		return out, true
+	}
+
+	// Named map types such as http.Header or url.Values
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		out := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			item := iter.Value().Interface()
+			if r.sensitiveKey(key) {
+				out[key] = Redacted
+			} else if masked, ok := r.redactValue(item); ok {
+				out[key] = masked
+			} else {
+				out[key] = item
+			}
+		}
+		return out, true
+	}
+
+	return value, false
+}
+
+// redactField returns the field with sensitive content masked
+func (r *redactor) redactField(field zapcore.Field) zapcore.Field {
+	if r.sensitiveKey(field.Key) {
+		switch field.Type {
+		case zapcore.SkipType, zapcore.NamespaceType:
+			return field
+		}
+		return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: Redacted}
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = r.redactString(field.String)
+	case zapcore.ByteStringType:
+		if b, ok := field.Interface.([]byte); ok {
+			field.Interface = []byte(r.redactString(string(b)))
+		}
+	case zapcore.StringerType:
+		if stringer, ok := field.Interface.(fmt.Stringer); ok {
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: r.redactString(stringer.String())}
+		}
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok && err != nil {
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: r.redactString(err.Error())}
+		}
+	case zapcore.ReflectType:
+		if masked, ok := r.redactValue(field.Interface); ok {
+			field.Interface = masked
+		}
+	case zapcore.ObjectMarshalerType:
+		if req, ok := field.Interface.(HTTPRequest); ok {
+			req.RequestURL = r.redactString(req.RequestURL)
+			req.Referer = r.redactString(req.Referer)
+			req.UserAgent = r.redactString(req.UserAgent)
+			field.Interface = req
+		}
+	}
+
+	return field
+}
+
+func (r *redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		out[i] = r.redactField(field)
+	}
+	return out
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum := 0
+	double := false
+	digits := 0
+
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+
+	return digits >= 13 && sum%10 == 0
+}
+
+// redactCore masks sensitive fields before they reach the wrapped core
+type redactCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+func newRedactCore(core zapcore.Core, r *redactor) zapcore.Core {
+	if r == nil {
+		return core
+	}
+	return &redactCore{Core: core, redactor: r}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{
+		Core:     c.Core.With(c.redactor.redactFields(fields)),
+		redactor: c.redactor,
+	}
+}
+
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.redactor.redactFields(fields))
+}
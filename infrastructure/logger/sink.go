@@ -0,0 +1,231 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Outputs accepted by SinkConfig.Output besides a file path
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// SinkConfig describes one log destination
+type SinkConfig struct {
+	// Output is stdout, stderr or a file path
+	Output string
+	// Format is console, json or gcp, empty uses the logger format
+	Format string
+	// Level is the minimum level written to this sink, empty writes every
+	// entry the logger level lets through
+	Level string
+}
+
+// RotationConfig controls rotation of file sinks. Rotation is disabled
+// when every field is zero.
+type RotationConfig struct {
+	// MaxSizeMB rotates a file once it reaches this size (100 MB when only
+	// other rotation settings are given)
+	MaxSizeMB int
+	// Interval also rotates files on a fixed schedule, e.g. 24h
+	Interval time.Duration
+	// MaxAge removes rotated files older than this
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep
+	MaxBackups int
+	// Compress gzips rotated files
+	Compress bool
+}
+
+func (r RotationConfig) enabled() bool {
+	return r.MaxSizeMB > 0 || r.Interval > 0 || r.MaxAge > 0 || r.MaxBackups > 0
+}
+
+// SamplingConfig logs the first Initial entries with the same level and
+// message every Tick, then only every Thereafter-th one. Sampling is
+// disabled when Initial is zero.
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+}
+
+// ParseSinks parses sinks separated by ';', each a comma-separated list of
+// output, format and level keys:
+//
+//	output=stdout,format=json;output=logs/app.log,format=console;output=logs/error.log,level=error
+func ParseSinks(spec string) ([]SinkConfig, error) {
+	var sinks []SinkConfig
+
+	for _, item := range strings.Split(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		var sink SinkConfig
+		for _, pair := range strings.Split(item, ",") {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid log sink option %q", pair)
+			}
+
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "output":
+				sink.Output = value
+			case "format":
+				sink.Format = strings.ToLower(value)
+			case "level":
+				sink.Level = value
+			default:
+				return nil, fmt.Errorf("unknown log sink option %q", key)
+			}
+		}
+
+		if sink.Output == "" {
+			return nil, fmt.Errorf("log sink %q has no output", item)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
+
+// newSinkCore builds the core writing to a single sink
+func newSinkCore(sink SinkConfig, config LogConfig, redactor *redactor) (zapcore.Core, error) {
+	format := sink.Format
+	if format == "" {
+		format = config.format()
+	}
+
+	terminal := sink.Output == OutputStdout || sink.Output == OutputStderr
+
+	var encoder zapcore.Encoder
+	switch format {
+	case FormatConsole:
+		encoderConfig := defaultEncoderConfig()
+		// In development mode, use colorful output for the terminal
+		if config.Environment == "development" && terminal {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	case FormatJSON:
+		encoder = zapcore.NewJSONEncoder(defaultEncoderConfig())
+	case FormatGCP:
+		encoder = zapcore.NewJSONEncoder(gcpEncoderConfig())
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	// Each sink filters on its own level, the logger levels apply first
+	var enabler zapcore.LevelEnabler = zapcore.DebugLevel
+	if sink.Level != "" {
+		level, err := ParseLevel(sink.Level)
+		if err != nil {
+			return nil, fmt.Errorf("log sink %s: %w", sink.Output, err)
+		}
+		enabler = level
+	}
+
+	writer, err := openSink(sink.Output, config.Rotation)
+	if err != nil {
+		return nil, err
+	}
+
+	core := zapcore.NewCore(encoder, writer, enabler)
+	if format == FormatGCP {
+		core = newGCPCore(core, config.GCPProjectID)
+	}
+
+	// Redact per sink so the sink level is still checked before writing
+	return newRedactCore(core, redactor), nil
+}
+
+// openSink opens stdout, stderr or a file, rotated when configured
+func openSink(output string, rotation RotationConfig) (zapcore.WriteSyncer, error) {
+	if output == OutputStdout || output == OutputStderr || !rotation.enabled() {
+		writer, _, err := zap.Open(output)
+		if err != nil {
+			return nil, fmt.Errorf("open log sink %s: %w", output, err)
+		}
+		return writer, nil
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   output,
+		MaxSize:    rotation.MaxSizeMB,
+		MaxAge:     days(rotation.MaxAge),
+		MaxBackups: rotation.MaxBackups,
+		LocalTime:  true,
+		Compress:   rotation.Compress,
+	}
+
+	if rotation.Interval > 0 {
+		rotateEvery(file, rotation.Interval)
+	}
+
+	return zapcore.AddSync(file), nil
+}
+
+// days converts a retention to lumberjack's whole days, rounding up so a
+// short retention does not disable the cleanup
+func days(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int((d + 24*time.Hour - 1) / (24 * time.Hour))
+}
+
+var (
+	rotationMu     sync.Mutex
+	rotationTimers = make(map[string]*time.Timer)
+)
+
+// rotateEvery rotates the file at each multiple of interval. Opening the
+// same file again replaces the previous schedule.
+func rotateEvery(file *lumberjack.Logger, interval time.Duration) {
+	rotationMu.Lock()
+	defer rotationMu.Unlock()
+
+	if timer, ok := rotationTimers[file.Filename]; ok {
+		timer.Stop()
+	}
+
+	var schedule func()
+	schedule = func() {
+		next := time.Until(time.Now().Truncate(interval).Add(interval))
+		rotationTimers[file.Filename] = time.AfterFunc(next, func() {
+			if err := file.Rotate(); err != nil {
+				fmt.Fprintf(os.Stderr, "log rotation of %s failed: %v\n", file.Filename, err)
+			}
+
+			rotationMu.Lock()
+			defer rotationMu.Unlock()
+			schedule()
+		})
+	}
+	schedule()
+}
+
+// newSamplerCore wraps core with zap's sampler when sampling is enabled
+func newSamplerCore(core zapcore.Core, sampling SamplingConfig) zapcore.Core {
+	if sampling.Initial <= 0 {
+		return core
+	}
+
+	tick := sampling.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	return zapcore.NewSamplerWithOptions(core, tick, sampling.Initial, sampling.Thereafter)
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestParseSinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []SinkConfig
+		wantErr bool
+	}{
+		{name: "empty", spec: " ; "},
+		{
+			name: "several",
+			spec: "output=stdout,format=JSON; output=logs/error.log, level=error",
+			want: []SinkConfig{
+				{Output: "stdout", Format: "json"},
+				{Output: "logs/error.log", Level: "error"},
+			},
+		},
+		{name: "missing output", spec: "format=json", wantErr: true},
+		{name: "unknown option", spec: "output=stdout,color=true", wantErr: true},
+		{name: "not a pair", spec: "stdout", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSinks(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSinks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSinks() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDays(t *testing.T) {
+	tests := map[time.Duration]int{
+		0:              0,
+		-time.Hour:     0,
+		time.Hour:      1,
+		24 * time.Hour: 1,
+		25 * time.Hour: 2,
+	}
+
+	for d, want := range tests {
+		if got := days(d); got != want {
+			t.Errorf("days(%v) = %d, want %d", d, got, want)
+		}
+	}
+}
+
+func TestSinkLevels(t *testing.T) {
+	dir := t.TempDir()
+	all := filepath.Join(dir, "all.log")
+	errorsOnly := filepath.Join(dir, "error.log")
+
+	l, err := NewLoggerWithConfigE(LogConfig{
+		Level: "debug",
+		Sinks: []SinkConfig{
+			{Output: all, Format: FormatJSON},
+			{Output: errorsOnly, Format: FormatJSON, Level: "error"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewLoggerWithConfigE() error = %v", err)
+	}
+	l.Debug("debug entry")
+	l.Error("error entry")
+	_ = l.ZapLogger().Sync()
+
+	for path, want := range map[string][]string{
+		all:        {"debug entry", "error entry"},
+		errorsOnly: {"error entry"},
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if len(lines) != len(want) {
+			t.Fatalf("%s has %d entries, want %d", filepath.Base(path), len(lines), len(want))
+		}
+		for i, msg := range want {
+			if !strings.Contains(lines[i], msg) {
+				t.Errorf("%s entry %d = %s, want %q", filepath.Base(path), i, lines[i], msg)
+			}
+		}
+	}
+}
+
+func TestSamplerCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	if got := newSamplerCore(core, SamplingConfig{}); got != core {
+		t.Error("newSamplerCore() wrapped the core with sampling disabled")
+	}
+
+	sampled := newSamplerCore(core, SamplingConfig{Initial: 2, Thereafter: 3, Tick: time.Minute})
+	for i := 0; i < 8; i++ {
+		entry := zapcore.Entry{Level: zapcore.InfoLevel, Message: "repeated", Time: time.Now()}
+		if checked := sampled.Check(entry, nil); checked != nil {
+			checked.Write()
+		}
+	}
+
+	// The first 2, then the 5th and 8th
+	if got := logs.Len(); got != 4 {
+		t.Errorf("sampled entries = %d, want 4", got)
+	}
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "http"
+
+// UnmatchedRoute labels requests that did not match a route, so scanners
+// probing random paths cannot create new series
+const UnmatchedRoute = "unmatched"
+
+// OtherMethod labels non standard HTTP methods
+const OtherMethod = "OTHER"
+
+// Registry holds the application metrics and the Go runtime collectors
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	requestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route template and status class.",
+	}, []string{"method", "route", "status"})
+
+	requestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status class.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	requestsInFlight = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests being served by method and route template.",
+	}, []string{"method", "route"})
+
+	rateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "HTTP requests rejected by the rate limiter by method and route template.",
+	}, []string{"method", "route"})
+
+	healthStatus = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "health_check_status",
+		Help: "Last result of each health check, 1 for the current status and 0 for the others.",
+	}, []string{"check", "status"})
+
+	healthDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "health_check_duration_seconds",
+		Help:    "Health check latency.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"check"})
+)
+
+// healthStatuses mirrors the module.Status* values, kept here so the
+// metrics package does not depend on the module system
+var healthStatuses = []string{"ok", "degraded", "down", "unknown"}
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RequestStarted increments the in-flight gauge, call the returned function
+// once the request completes
+func RequestStarted(method, route string) func(status int) {
+	method, route = Method(method), Route(route)
+	start := time.Now()
+
+	inFlight := requestsInFlight.WithLabelValues(method, route)
+	inFlight.Inc()
+
+	return func(status int) {
+		inFlight.Dec()
+
+		class := StatusClass(status)
+		requestsTotal.WithLabelValues(method, route, class).Inc()
+		requestDuration.WithLabelValues(method, route, class).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RateLimited counts a request rejected by the rate limiter
+func RateLimited(method, route string) {
+	rateLimited.WithLabelValues(Method(method), Route(route)).Inc()
+}
+
+// HealthCheck records the result of a health check
+func HealthCheck(check, status string, duration time.Duration) {
+	for _, s := range healthStatuses {
+		value := 0.0
+		if s == status {
+			value = 1
+		}
+		healthStatus.WithLabelValues(check, s).Set(value)
+	}
+	healthDuration.WithLabelValues(check).Observe(duration.Seconds())
+}
+
+// Route returns the label for a route template
+func Route(route string) string {
+	if route == "" {
+		return UnmatchedRoute
+	}
+	return route
+}
+
+// Method returns the label for an HTTP method
+func Method(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return OtherMethod
+}
+
+// StatusClass returns "2xx", "4xx", ... for a status code
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLabels(t *testing.T) {
+	if got := Route(""); got != UnmatchedRoute {
+		t.Errorf("Route(\"\") = %q, want %q", got, UnmatchedRoute)
+	}
+	if got := Route("/api/users/:id"); got != "/api/users/:id" {
+		t.Errorf("Route() = %q, want the template", got)
+	}
+	if got := Method(http.MethodPatch); got != http.MethodPatch {
+		t.Errorf("Method(PATCH) = %q", got)
+	}
+	if got := Method("PROPFIND"); got != OtherMethod {
+		t.Errorf("Method(PROPFIND) = %q, want %q", got, OtherMethod)
+	}
+
+	for status, want := range map[int]string{200: "2xx", 404: "4xx", 599: "5xx", 99: "unknown", 600: "unknown"} {
+		if got := StatusClass(status); got != want {
+			t.Errorf("StatusClass(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestRequestStarted(t *testing.T) {
+	done := RequestStarted("PROPFIND", "")
+	if got := testutil.ToFloat64(requestsInFlight.WithLabelValues(OtherMethod, UnmatchedRoute)); got != 1 {
+		t.Errorf("in flight = %v, want 1", got)
+	}
+
+	done(http.StatusNotFound)
+	if got := testutil.ToFloat64(requestsInFlight.WithLabelValues(OtherMethod, UnmatchedRoute)); got != 0 {
+		t.Errorf("in flight = %v, want 0", got)
+	}
+	if got := testutil.ToFloat64(requestsTotal.WithLabelValues(OtherMethod, UnmatchedRoute, "4xx")); got != 1 {
+		t.Errorf("requests = %v, want 1", got)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	HealthCheck("firestore", "degraded", time.Millisecond)
+
+	for _, status := range healthStatuses {
+		want := 0.0
+		if status == "degraded" {
+			want = 1
+		}
+		if got := testutil.ToFloat64(healthStatus.WithLabelValues("firestore", status)); got != want {
+			t.Errorf("health_check_status{status=%q} = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang-template/configs"
+	"golang-template/infrastructure/logger"
+)
+
+// Listener kinds accepted by SERVER_LISTENERS
+const (
+	ListenerTCP     = "tcp"
+	ListenerUnix    = "unix"
+	ListenerSystemd = "systemd"
+)
+
+// systemd passes activated sockets starting at file descriptor 3
+const listenFdsStart = 3
+
+// Listen opens every listener configured in SERVER_LISTENERS, or reuses the
+// listeners handed over by a graceful restart. On error the listeners opened
+// so far are closed.
+func Listen(cfg *configs.Config, log logger.Logger) ([]net.Listener, error) {
+	inherited, ok, err := inheritedListeners()
+	if err != nil {
+		return nil, fmt.Errorf("inherit listeners: %w", err)
+	}
+	if ok {
+		for _, l := range inherited {
+			log.Info("Listener inherited", "network", l.Addr().Network(), "address", l.Addr().String())
+		}
+		return inherited, nil
+	}
+
+	var listeners []net.Listener
+
+	closeAll := func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}
+
+	for _, kind := range cfg.ServerListeners {
+		var (
+			opened []net.Listener
+			err    error
+		)
+
+		switch strings.TrimSpace(kind) {
+		case ListenerTCP:
+			var l net.Listener
+			l, err = net.Listen("tcp", fmt.Sprintf(":%d", cfg.Port))
+			opened = append(opened, l)
+		case ListenerUnix:
+			var l net.Listener
+			l, err = listenUnix(cfg.ServerUnixSocket, cfg.ServerUnixSocketMode)
+			opened = append(opened, l)
+		case ListenerSystemd:
+			opened, err = systemdListeners()
+		default:
+			err = fmt.Errorf("unknown listener %q", kind)
+		}
+
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("listen %s: %w", kind, err)
+		}
+
+		for _, l := range opened {
+			log.Info("Listener opened", "network", l.Addr().Network(), "address", l.Addr().String())
+		}
+		listeners = append(listeners, opened...)
+	}
+
+	if len(listeners) == 0 {
+		return nil, errors.New("no listeners configured")
+	}
+
+	return listeners, nil
+}
+
+// listenUnix listens on a Unix domain socket, replacing a stale socket file
+// left by a previous run, and applies the configured permissions
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if path == "" {
+		return nil, errors.New("SERVER_UNIX_SOCKET is required")
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+// systemdListeners returns the sockets passed by systemd socket activation
+// (LISTEN_PID/LISTEN_FDS) and unsets the variables so child processes do
+// not inherit them
+func systemdListeners() ([]net.Listener, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, errors.New("no sockets passed by systemd (LISTEN_PID does not match)")
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return nil, errors.New("no sockets passed by systemd (LISTEN_FDS is empty)")
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	listeners := make([]net.Listener, 0, count)
+	for i := 0; i < count; i++ {
+		name := "systemd"
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		file := os.NewFile(uintptr(listenFdsStart+i), name)
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("socket %s: %w", name, err)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
@@ -0,0 +1,189 @@
+//go:build !windows
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang-template/infrastructure/logger"
+)
+
+// Environment used to hand the listeners to the new process
+const (
+	envRestartFds     = "GRACEFUL_RESTART_FDS"
+	envRestartReadyFd = "GRACEFUL_RESTART_READY_FD"
+)
+
+var restarting atomic.Bool
+
+// WatchRestartSignal calls fn for every SIGUSR2 until ctx is done
+func WatchRestartSignal(ctx context.Context, fn func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGUSR2)
+	defer signal.Stop(sig)
+
+	for {
+		select {
+		case <-sig:
+			fn()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Restart starts a new copy of the running binary that inherits the
+// listeners and waits until it reports readiness. On success the caller
+// should drain and exit; on error the current process keeps serving.
+func Restart(listeners []net.Listener, timeout time.Duration, log logger.Logger) error {
+	if !restarting.CompareAndSwap(false, true) {
+		return errors.New("restart already in progress")
+	}
+	defer restarting.Store(false)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	files := make([]*os.File, 0, len(listeners)+1)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, l := range listeners {
+		fl, ok := l.(interface{ File() (*os.File, error) })
+		if !ok {
+			return fmt.Errorf("listener %s cannot be inherited", l.Addr())
+		}
+
+		file, err := fl.File()
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+	}
+
+	readyRead, readyWrite, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyRead.Close()
+	files = append(files, readyWrite)
+
+	env := append(os.Environ(),
+		envRestartFds+"="+strconv.Itoa(len(listeners)),
+		envRestartReadyFd+"="+strconv.Itoa(listenFdsStart+len(listeners)),
+	)
+
+	process, err := os.StartProcess(executable, os.Args, &os.ProcAttr{
+		Env:   env,
+		Files: append([]*os.File{os.Stdin, os.Stdout, os.Stderr}, files...),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only the child keeps the write end, so EOF means it exited
+	readyWrite.Close()
+	files = files[:len(files)-1]
+
+	log.Info("Started new process, waiting for readiness", "pid", process.Pid)
+
+	ready := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1)
+		_, err := readyRead.Read(buf)
+		ready <- err
+	}()
+
+	select {
+	case err := <-ready:
+		if err != nil {
+			process.Release()
+			return fmt.Errorf("new process %d exited before becoming ready", process.Pid)
+		}
+	case <-time.After(timeout):
+		process.Kill()
+		process.Release()
+		return fmt.Errorf("new process %d not ready after %s", process.Pid, timeout)
+	}
+
+	// The new process owns the socket files now
+	for _, l := range listeners {
+		if ul, ok := l.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+
+	log.Info("New process is ready, handing off", "pid", process.Pid)
+	return process.Release()
+}
+
+// NotifyReady tells the parent process that started this one through
+// Restart that the listeners are being served
+func NotifyReady() error {
+	fdStr := os.Getenv(envRestartReadyFd)
+	if fdStr == "" {
+		return nil
+	}
+	os.Unsetenv(envRestartReadyFd)
+
+	fd, err := strconv.Atoi(fdStr)
+	if err != nil {
+		return err
+	}
+
+	file := os.NewFile(uintptr(fd), "ready")
+	defer file.Close()
+
+	_, err = file.Write([]byte{1})
+	return err
+}
+
+// inheritedListeners returns the listeners passed by Restart, if any
+func inheritedListeners() ([]net.Listener, bool, error) {
+	countStr := os.Getenv(envRestartFds)
+	if countStr == "" {
+		return nil, false, nil
+	}
+	os.Unsetenv(envRestartFds)
+
+	count, err := strconv.Atoi(countStr)
+	if err != nil {
+		return nil, true, err
+	}
+
+	listeners := make([]net.Listener, 0, count)
+	for i := 0; i < count; i++ {
+		file := os.NewFile(uintptr(listenFdsStart+i), "inherited")
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, true, err
+		}
+
+		// This process owns the socket file from now on
+		if ul, ok := listener.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(true)
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, true, nil
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"golang-template/infrastructure/logger"
+)
+
+// WatchRestartSignal is a no-op, graceful restart is not supported on Windows
+func WatchRestartSignal(ctx context.Context, fn func()) {
+	<-ctx.Done()
+}
+
+// Restart is not supported on Windows
+func Restart(listeners []net.Listener, timeout time.Duration, log logger.Logger) error {
+	return errors.New("graceful restart is not supported on windows")
+}
+
+// NotifyReady is a no-op on Windows
+func NotifyReady() error {
+	return nil
+}
+
+func inheritedListeners() ([]net.Listener, bool, error) {
+	return nil, false, nil
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"golang-template/configs"
+	"golang-template/infrastructure/logger"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Server wraps http.Server and serves TLS when a certificate is configured
+type Server struct {
+	*http.Server
+	tls bool
+}
+
+// New creates the HTTP server from the configuration
+func New(cfg *configs.Config, handler http.Handler, log logger.Logger) (*Server, error) {
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.ServerReadTimeout,
+		ReadHeaderTimeout: cfg.ServerReadHeaderTimeout,
+		WriteTimeout:      cfg.ServerWriteTimeout,
+		IdleTimeout:       cfg.ServerIdleTimeout,
+		MaxHeaderBytes:    cfg.ServerMaxHeaderBytes,
+	}
+	srv.SetKeepAlivesEnabled(cfg.ServerKeepAlives)
+
+	useTLS := cfg.TLSCertFile != "" || cfg.TLSKeyFile != ""
+	if useTLS {
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return nil, fmt.Errorf("both TLS_CERT_FILE and TLS_KEY_FILE are required")
+		}
+
+		tlsConfig, err := newTLSConfig(cfg, log)
+		if err != nil {
+			return nil, err
+		}
+		srv.TLSConfig = tlsConfig
+
+		log.Info("TLS enabled", "cert", cfg.TLSCertFile, "clientAuth", tlsConfig.ClientAuth.String())
+	} else if cfg.ServerH2C {
+		// HTTP/2 over cleartext for proxies that speak h2c upstream
+		srv.Handler = h2c.NewHandler(handler, &http2.Server{
+			IdleTimeout: cfg.ServerIdleTimeout,
+		})
+
+		log.Info("h2c enabled")
+	}
+
+	return &Server{Server: srv, tls: useTLS}, nil
+}
+
+// Serve accepts connections on the listener until the server is shut down
+func (s *Server) Serve(listener net.Listener) error {
+	if s.tls {
+		return s.Server.ServeTLS(listener, "", "")
+	}
+	return s.Server.Serve(listener)
+}
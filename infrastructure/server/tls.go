@@ -0,0 +1,188 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-template/configs"
+	"golang-template/infrastructure/logger"
+)
+
+// certReloader serves the certificate from disk and reloads it when the
+// certificate or key file changes
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	log      logger.Logger
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func newCertReloader(certFile, keyFile string, interval time.Duration, log logger.Logger) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+		log:      log,
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.maybeReload()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// maybeReload checks the files at most once per interval and keeps serving
+// the previous certificate if the new one cannot be loaded
+func (r *certReloader) maybeReload() {
+	if r.interval <= 0 {
+		return
+	}
+
+	r.mu.RLock()
+	due := time.Since(r.lastCheck) >= r.interval
+	r.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	// Only the handshake that claims the check reloads, others that saw it
+	// due concurrently keep the current certificate
+	r.mu.Lock()
+	if time.Since(r.lastCheck) < r.interval {
+		r.mu.Unlock()
+		return
+	}
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil {
+		r.log.Warn("Failed to stat TLS certificate", "error", err)
+		return
+	}
+
+	r.mu.RLock()
+	changed := modTime.After(r.modTime)
+	r.mu.RUnlock()
+	if !changed {
+		return
+	}
+
+	if err := r.load(); err != nil {
+		r.log.Error("Failed to reload TLS certificate", "error", err)
+		return
+	}
+
+	r.log.Info("TLS certificate reloaded", "cert", r.certFile)
+}
+
+func (r *certReloader) load() error {
+	modTime, err := latestModTime(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS key pair: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.modTime = modTime
+	r.lastCheck = time.Now()
+	r.mu.Unlock()
+
+	return nil
+}
+
+func latestModTime(paths ...string) (time.Time, error) {
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// newTLSConfig builds the TLS configuration with certificate hot-reload and
+// optional client certificate verification
+func newTLSConfig(cfg *configs.Config, log logger.Logger) (*tls.Config, error) {
+	reloader, err := newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSReloadInterval, log)
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth, err := parseClientAuth(cfg.TLSClientAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+		ClientAuth:     clientAuth,
+	}
+
+	if cfg.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read TLS client CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+	}
+
+	if clientAuth >= tls.VerifyClientCertIfGiven && tlsConfig.ClientCAs == nil {
+		return nil, fmt.Errorf("TLS_CLIENT_AUTH=%s requires TLS_CLIENT_CA_FILE", cfg.TLSClientAuth)
+	}
+
+	return tlsConfig, nil
+}
+
+func parseClientAuth(value string) (tls.ClientAuthType, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "require":
+		return tls.RequireAnyClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require_and_verify":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("invalid TLS_CLIENT_AUTH %q", value)
+	}
+}
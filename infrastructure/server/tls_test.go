@@ -0,0 +1,109 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang-template/infrastructure/logger"
+)
+
+// countingLogger counts the reloads reported by the reloader
+type countingLogger struct {
+	logger.Logger
+	reloads atomic.Int32
+}
+
+func (l *countingLogger) Info(msg string, keysAndValues ...interface{}) {
+	if msg == "TLS certificate reloaded" {
+		l.reloads.Add(1)
+	}
+}
+
+func writeTestCert(t *testing.T, certFile, keyFile string, serial int64, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{certFile, keyFile} {
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func serial(t *testing.T, cert *tls.Certificate) int64 {
+	t.Helper()
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.SerialNumber.Int64()
+}
+
+func TestCertReloaderReloadsOnce(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeTestCert(t, certFile, keyFile, 1, time.Now().Add(-time.Minute))
+
+	log := &countingLogger{Logger: logger.Default()}
+	r, err := newCertReloader(certFile, keyFile, 10*time.Millisecond, log)
+	if err != nil {
+		t.Fatalf("newCertReloader() error = %v", err)
+	}
+
+	writeTestCert(t, certFile, keyFile, 2, time.Now())
+	time.Sleep(20 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = r.GetCertificate(nil)
+		}()
+	}
+	wg.Wait()
+
+	cert, _ := r.GetCertificate(nil)
+	if got := serial(t, cert); got != 2 {
+		t.Errorf("serial = %d, want 2", got)
+	}
+	if got := log.reloads.Load(); got != 1 {
+		t.Errorf("reloads = %d, want 1", got)
+	}
+}
@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"golang-template/pkg/common/principal"
+	"golang-template/pkg/common/requestid"
+)
+
+// Actions recorded for entity changes
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// SystemActor is the actor of changes made by the server itself, e.g. the
+// scheduled purge of soft deleted entities
+const SystemActor = "system"
+
+// Event records one change of an entity
+type Event struct {
+	ID string `json:"id" firestore:"-"`
+	// Action is one of the Action constants
+	Action string `json:"action" firestore:"action"`
+	// Entity is the collection of the entity
+	Entity   string `json:"entity" firestore:"entity"`
+	EntityID string `json:"entityId" firestore:"entityId"`
+	// Actor is the uid of the request principal, empty for anonymous requests
+	Actor     string `json:"actor,omitempty" firestore:"actor"`
+	RequestID string `json:"requestId,omitempty" firestore:"requestId,omitempty"`
+	// Changes holds the fields that differ between before and after
+	Changes   map[string]Change `json:"changes,omitempty" firestore:"changes,omitempty"`
+	Timestamp time.Time         `json:"timestamp" firestore:"timestamp"`
+}
+
+// Change is the value of a field before and after an event, nil when the
+// field did not exist
+type Change struct {
+	Before interface{} `json:"before" firestore:"before"`
+	After  interface{} `json:"after" firestore:"after"`
+}
+
+// Filter selects events, zero fields match everything. Events are returned
+// newest first.
+type Filter struct {
+	Entity   string
+	EntityID string
+	Actor    string
+	From     time.Time
+	To       time.Time
+	Limit    int
+	// Cursor is the ID of the last event of the previous page
+	Cursor string
+}
+
+// Store persists events. Events are append-only, there is no way to change
+// or remove one.
+type Store interface {
+	Record(ctx context.Context, event Event) error
+	// Query returns the events matching the filter and the cursor of the
+	// next page, empty on the last page
+	Query(ctx context.Context, filter Filter) ([]Event, string, error)
+}
+
+// NewEvent creates an event for the actor and request of ctx from the
+// entity states before and after the change, nil for creations and
+// deletions
+func NewEvent(ctx context.Context, action, entity, entityID string, before, after map[string]interface{}) Event {
+	return Event{
+		Action:    action,
+		Entity:    entity,
+		EntityID:  entityID,
+		Actor:     principal.FromContext(ctx),
+		RequestID: requestid.FromContext(ctx),
+		Changes:   Diff(before, after),
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// Fields returns the fields of an entity as they are sent to clients,
+// following the json tags so hidden fields are never recorded
+func Fields(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	err = json.Unmarshal(raw, &fields)
+	return fields, err
+}
+
+// Diff returns the fields added, removed or changed between two states
+func Diff(before, after map[string]interface{}) map[string]Change {
+	changes := make(map[string]Change)
+	for key, value := range before {
+		if !reflect.DeepEqual(value, after[key]) {
+			changes[key] = Change{Before: value, After: after[key]}
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changes[key] = Change{After: value}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"golang-template/pkg/common/principal"
+	"golang-template/pkg/common/requestid"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before map[string]interface{}
+		after  map[string]interface{}
+		want   map[string]Change
+	}{
+		{
+			name:  "create",
+			after: map[string]interface{}{"name": "a"},
+			want:  map[string]Change{"name": {After: "a"}},
+		},
+		{
+			name:   "delete",
+			before: map[string]interface{}{"name": "a"},
+			want:   map[string]Change{"name": {Before: "a"}},
+		},
+		{
+			name:   "update",
+			before: map[string]interface{}{"name": "a", "age": 1.0, "tags": []interface{}{"x"}, "old": true},
+			after:  map[string]interface{}{"name": "b", "age": 1.0, "tags": []interface{}{"x"}, "new": true},
+			want: map[string]Change{
+				"name": {Before: "a", After: "b"},
+				"old":  {Before: true},
+				"new":  {After: true},
+			},
+		},
+		{
+			name:   "unchanged",
+			before: map[string]interface{}{"name": "a"},
+			after:  map[string]interface{}{"name": "a"},
+			want:   map[string]Change{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	type user struct {
+		Name     string `json:"name"`
+		Password string `json:"-"`
+		Note     string `json:"note,omitempty"`
+	}
+
+	got, err := Fields(user{Name: "a", Password: "secret"})
+	if err != nil {
+		t.Fatalf("Fields() error = %v", err)
+	}
+	if want := map[string]interface{}{"name": "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	ctx := principal.NewContext(requestid.NewContext(context.Background(), "req-1"), "uid-1")
+
+	event := NewEvent(ctx, ActionUpdate, "users", "u1",
+		map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"})
+
+	if event.Action != ActionUpdate || event.Entity != "users" || event.EntityID != "u1" {
+		t.Errorf("NewEvent() = %+v", event)
+	}
+	if event.Actor != "uid-1" || event.RequestID != "req-1" {
+		t.Errorf("NewEvent() actor %q request %q, want uid-1 and req-1", event.Actor, event.RequestID)
+	}
+	if event.Timestamp.IsZero() || event.Timestamp.Location().String() != "UTC" {
+		t.Errorf("Timestamp = %v, want the current UTC time", event.Timestamp)
+	}
+	if want := (map[string]Change{"name": {Before: "a", After: "b"}}); !reflect.DeepEqual(event.Changes, want) {
+		t.Errorf("Changes = %v, want %v", event.Changes, want)
+	}
+}
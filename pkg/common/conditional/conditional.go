@@ -0,0 +1,158 @@
+package conditional
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-template/pkg/common/errors"
+)
+
+// Precondition holds the If-Match and If-Unmodified-Since headers of a
+// write request. Repositories check it against the stored entity with the
+// addressed ID so stale writes fail with 412 PRECONDITION_FAILED, other
+// entities written by the same request are not affected.
+type Precondition struct {
+	// ID of the entity the request addresses, the precondition applies to
+	// no entity when empty
+	ID                string
+	IfMatch           []string
+	IfUnmodifiedSince time.Time
+}
+
+// For returns the precondition when it addresses the entity with the given
+// ID, otherwise an empty precondition
+func (p Precondition) For(id string) Precondition {
+	if p.ID == "" || p.ID != id {
+		return Precondition{}
+	}
+	return p
+}
+
+// Empty reports whether the request had no precondition
+func (p Precondition) Empty() bool {
+	return len(p.IfMatch) == 0 && p.IfUnmodifiedSince.IsZero()
+}
+
+// Check returns a PRECONDITION_FAILED error when the current state of a
+// resource, given by its ETag and last modification time, does not
+// satisfy the precondition. If-Match uses the strong comparison and takes
+// precedence over If-Unmodified-Since.
+func (p Precondition) Check(etag string, lastModified time.Time) error {
+	if len(p.IfMatch) > 0 {
+		for _, tag := range p.IfMatch {
+			if tag == "*" || StrongMatch(tag, etag) {
+				return nil
+			}
+		}
+		return preconditionFailed(etag)
+	}
+
+	if !p.IfUnmodifiedSince.IsZero() && !lastModified.IsZero() &&
+		lastModified.Truncate(time.Second).After(p.IfUnmodifiedSince) {
+		return preconditionFailed(etag)
+	}
+
+	return nil
+}
+
+func preconditionFailed(etag string) error {
+	return errors.FromCode(errors.CodePreconditionFailed, nil).
+		WithDetails(map[string]string{"etag": etag})
+}
+
+// FromRequest reads the precondition headers of r for the entity with the
+// given ID
+func FromRequest(r *http.Request, id string) Precondition {
+	p := Precondition{
+		ID:      id,
+		IfMatch: ParseTags(r.Header.Get("If-Match")),
+	}
+	if since, err := http.ParseTime(r.Header.Get("If-Unmodified-Since")); err == nil {
+		p.IfUnmodifiedSince = since
+	}
+	return p
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the precondition
+func NewContext(ctx context.Context, p Precondition) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the precondition stored in ctx, empty when none
+func FromContext(ctx context.Context) Precondition {
+	p, _ := ctx.Value(contextKey{}).(Precondition)
+	return p
+}
+
+// StrongTag returns a strong ETag of a representation, for byte identical
+// bodies
+func StrongTag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// WeakTag returns a weak ETag of a representation, for semantically
+// equivalent bodies, e.g. when a proxy compresses responses
+func WeakTag(body []byte) string {
+	return "W/" + StrongTag(body)
+}
+
+// Updated is implemented by every entity
+type Updated interface {
+	GetUpdatedAt() time.Time
+}
+
+// versioned is implemented by entities with optimistic concurrency control
+type versioned interface {
+	GetVersion() int64
+}
+
+// EntityTag returns the ETag of an entity state from its version, or its
+// update time for entities without one. It is strong so it can be used
+// with If-Match, which ignores weak tags.
+func EntityTag(e Updated) string {
+	if v, ok := e.(versioned); ok && v.GetVersion() > 0 {
+		return `"v` + strconv.FormatInt(v.GetVersion(), 10) + `"`
+	}
+	return `"` + strconv.FormatInt(e.GetUpdatedAt().UnixNano(), 36) + `"`
+}
+
+// ParseTags splits an If-Match or If-None-Match header, "*" is kept as is
+func ParseTags(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// StrongMatch compares two ETags with the strong comparison: both must be
+// strong and identical
+func StrongMatch(a, b string) bool {
+	return !strings.HasPrefix(a, "W/") && !strings.HasPrefix(b, "W/") && a == b
+}
+
+// WeakMatch compares two ETags ignoring the weak indicator
+func WeakMatch(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
+
+// NoneMatch reports whether If-None-Match tags do not match etag, i.e.
+// the client copy is stale
+func NoneMatch(tags []string, etag string) bool {
+	for _, tag := range tags {
+		if tag == "*" || WeakMatch(tag, etag) {
+			return false
+		}
+	}
+	return true
+}
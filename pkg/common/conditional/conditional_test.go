@@ -0,0 +1,158 @@
+package conditional
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang-template/pkg/common/errors"
+)
+
+type entity struct {
+	updatedAt time.Time
+	version   int64
+}
+
+func (e entity) GetUpdatedAt() time.Time { return e.updatedAt }
+func (e entity) GetVersion() int64       { return e.version }
+
+type unversioned struct {
+	updatedAt time.Time
+}
+
+func (e unversioned) GetUpdatedAt() time.Time { return e.updatedAt }
+
+func TestPreconditionCheck(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name string
+		p    Precondition
+		etag string
+		want bool
+	}{
+		{name: "empty", p: Precondition{}, etag: `"v2"`, want: true},
+		{name: "match", p: Precondition{IfMatch: []string{`"v1"`, `"v2"`}}, etag: `"v2"`, want: true},
+		{name: "any", p: Precondition{IfMatch: []string{"*"}}, etag: `"v2"`, want: true},
+		{name: "stale", p: Precondition{IfMatch: []string{`"v1"`}}, etag: `"v2"`, want: false},
+		{name: "weak never matches", p: Precondition{IfMatch: []string{`W/"v2"`}}, etag: `"v2"`, want: false},
+		{
+			name: "If-Match takes precedence",
+			p:    Precondition{IfMatch: []string{`"v2"`}, IfUnmodifiedSince: modified.Add(-time.Hour)},
+			etag: `"v2"`,
+			want: true,
+		},
+		{name: "unmodified", p: Precondition{IfUnmodifiedSince: modified.Truncate(time.Second)}, etag: `"v2"`, want: true},
+		{name: "modified", p: Precondition{IfUnmodifiedSince: modified.Add(-time.Second)}, etag: `"v2"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.p.Check(tt.etag, modified)
+			if (err == nil) != tt.want {
+				t.Fatalf("Check() error = %v, want passed %v", err, tt.want)
+			}
+			if err == nil {
+				return
+			}
+
+			appErr, ok := errors.As(err)
+			if !ok || appErr.Code != errors.CodePreconditionFailed {
+				t.Fatalf("Check() error = %v, want PRECONDITION_FAILED", err)
+			}
+			if details := appErr.Details.(map[string]string); details["etag"] != tt.etag {
+				t.Errorf("details = %v, want the current ETag", details)
+			}
+		})
+	}
+}
+
+func TestPreconditionFor(t *testing.T) {
+	p := Precondition{ID: "a", IfMatch: []string{`"v1"`}}
+
+	if got := p.For("a"); !reflect.DeepEqual(got, p) {
+		t.Errorf("For(a) = %+v, want %+v", got, p)
+	}
+	if got := p.For("b"); !got.Empty() {
+		t.Errorf("For(b) = %+v, want empty", got)
+	}
+	if got := (Precondition{IfMatch: []string{"*"}}).For(""); !got.Empty() {
+		t.Errorf("For(\"\") without an ID = %+v, want empty", got)
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	r := httptest.NewRequest("PUT", "/users/a", nil)
+	r.Header.Set("If-Match", `"v1", "v2"`)
+	r.Header.Set("If-Unmodified-Since", "Wed, 01 May 2024 12:00:00 GMT")
+
+	p := FromRequest(r, "a")
+	want := Precondition{
+		ID:                "a",
+		IfMatch:           []string{`"v1"`, `"v2"`},
+		IfUnmodifiedSince: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("FromRequest() = %+v, want %+v", p, want)
+	}
+
+	if p := FromRequest(httptest.NewRequest("PUT", "/users/a", nil), "a"); !p.Empty() {
+		t.Errorf("FromRequest() without headers = %+v, want empty", p)
+	}
+}
+
+func TestEntityTag(t *testing.T) {
+	updated := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name string
+		e    Updated
+		want string
+	}{
+		{name: "versioned", e: entity{updatedAt: updated, version: 3}, want: `"v3"`},
+		{name: "version zero", e: entity{updatedAt: updated}, want: `"` + strconv.FormatInt(updated.UnixNano(), 36) + `"`},
+		{name: "unversioned", e: unversioned{updatedAt: updated}, want: `"` + strconv.FormatInt(updated.UnixNano(), 36) + `"`},
+	}
+
+	for _, tt := range tests {
+		if got := EntityTag(tt.e); got != tt.want {
+			t.Errorf("%s: EntityTag() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	if got := ParseTags(` "a", W/"b" ,,*`); !reflect.DeepEqual(got, []string{`"a"`, `W/"b"`, "*"}) {
+		t.Errorf("ParseTags() = %v", got)
+	}
+
+	if StrongTag([]byte("body")) != StrongTag([]byte("body")) || StrongTag([]byte("a")) == StrongTag([]byte("b")) {
+		t.Error("StrongTag() is not a stable hash of the body")
+	}
+	if got := WeakTag([]byte("body")); got != "W/"+StrongTag([]byte("body")) {
+		t.Errorf("WeakTag() = %s", got)
+	}
+
+	tests := []struct {
+		a, b         string
+		strong, weak bool
+	}{
+		{a: `"a"`, b: `"a"`, strong: true, weak: true},
+		{a: `W/"a"`, b: `"a"`, strong: false, weak: true},
+		{a: `"a"`, b: `"b"`, strong: false, weak: false},
+	}
+	for _, tt := range tests {
+		if got := StrongMatch(tt.a, tt.b); got != tt.strong {
+			t.Errorf("StrongMatch(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.strong)
+		}
+		if got := WeakMatch(tt.a, tt.b); got != tt.weak {
+			t.Errorf("WeakMatch(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.weak)
+		}
+	}
+
+	if NoneMatch([]string{`W/"a"`}, `"a"`) || NoneMatch([]string{"*"}, `"a"`) || !NoneMatch([]string{`"b"`}, `"a"`) {
+		t.Error("NoneMatch() does not use the weak comparison")
+	}
+}
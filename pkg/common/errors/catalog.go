@@ -0,0 +1,236 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language of Definition.Message
+var DefaultLanguage = language.English
+
+// Definition describes a stable error code. Message is a template where
+// {name} is replaced by the parameter of the same name and {name|default}
+// falls back to default when the parameter is missing.
+type Definition struct {
+	Code    string
+	Status  int
+	Message string
+	// DocsURL links to the documentation of the code, empty uses
+	// ERROR_TYPE_BASE_URL
+	DocsURL string
+	// Translations of Message keyed by BCP 47 language tag, e.g. "id" or "pt-BR"
+	Translations map[string]string
+}
+
+// Params are the values substituted in message templates
+type Params map[string]interface{}
+
+type catalogEntry struct {
+	def     Definition
+	tags    []language.Tag
+	matcher language.Matcher
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = newCatalog(builtinDefinitions)
+)
+
+var builtinDefinitions = []Definition{
+	{Code: CodeBadRequest, Status: http.StatusBadRequest, Message: "Bad request"},
+	{Code: CodeUnauthorized, Status: http.StatusUnauthorized, Message: "Unauthorized access"},
+	{Code: CodeForbidden, Status: http.StatusForbidden, Message: "Forbidden access"},
+	{Code: CodeNotFound, Status: http.StatusNotFound, Message: "{resource|Resource} not found"},
+	{Code: CodeMethodNotAllowed, Status: http.StatusMethodNotAllowed, Message: "Method not allowed"},
+	{Code: CodeNotAcceptable, Status: http.StatusNotAcceptable, Message: "None of the accepted media types can represent the response"},
+	{Code: CodeConflict, Status: http.StatusConflict, Message: "Resource already exists or was modified"},
+	{Code: CodeInternalServerError, Status: http.StatusInternalServerError, Message: "An unexpected error occurred"},
+	{Code: CodeValidationError, Status: http.StatusBadRequest, Message: "Validation failed"},
+	{Code: CodeNotImplemented, Status: http.StatusNotImplemented, Message: "Not implemented"},
+	{Code: CodeTooManyRequests, Status: http.StatusTooManyRequests, Message: "Rate limit exceeded"},
+	{Code: CodePreconditionFailed, Status: http.StatusPreconditionFailed, Message: "Precondition failed"},
+	{Code: CodeServiceUnavailable, Status: http.StatusServiceUnavailable, Message: "Service temporarily unavailable"},
+	{Code: CodeTimeout, Status: http.StatusGatewayTimeout, Message: "The request timed out"},
+	{Code: CodeRequestCanceled, Status: StatusClientClosedRequest, Message: "Request canceled by the client"},
+	{Code: CodeBuildInfoUnavailable, Status: http.StatusNotFound, Message: "Build information is not available"},
+}
+
+func newCatalog(defs []Definition) map[string]*catalogEntry {
+	entries := make(map[string]*catalogEntry, len(defs))
+	for _, def := range defs {
+		if err := addDefinition(entries, def); err != nil {
+			panic(err)
+		}
+	}
+	return entries
+}
+
+// Register adds error definitions to the catalog, typically from a module
+// package init. It panics on invalid or duplicate codes.
+func Register(defs ...Definition) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	for _, def := range defs {
+		if err := addDefinition(catalog, def); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterTranslations adds the messages of one language for registered
+// codes, e.g. RegisterTranslations("id", map[string]string{CodeNotFound:
+// "{resource|Data} tidak ditemukan"}). It panics on unknown codes.
+func RegisterTranslations(lang string, messages map[string]string) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		panic(fmt.Sprintf("errors: RegisterTranslations invalid language %q: %v", lang, err))
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	for code, message := range messages {
+		entry, ok := catalog[code]
+		if !ok {
+			panic("errors: RegisterTranslations called for unknown code " + code)
+		}
+
+		def := entry.def
+		translations := make(map[string]string, len(def.Translations)+1)
+		for k, v := range def.Translations {
+			translations[k] = v
+		}
+		translations[tag.String()] = message
+		def.Translations = translations
+
+		catalog[code] = newEntry(def)
+	}
+}
+
+func addDefinition(entries map[string]*catalogEntry, def Definition) error {
+	if def.Code == "" {
+		return fmt.Errorf("errors: Register definition without code")
+	}
+	if def.Status < 400 || def.Status > 599 {
+		return fmt.Errorf("errors: Register code %s with invalid status %d", def.Code, def.Status)
+	}
+	if _, exists := entries[def.Code]; exists {
+		return fmt.Errorf("errors: Register called twice for code %s", def.Code)
+	}
+
+	translations := make(map[string]string, len(def.Translations))
+	for lang, message := range def.Translations {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return fmt.Errorf("errors: Register code %s: invalid language %q: %w", def.Code, lang, err)
+		}
+		translations[tag.String()] = message
+	}
+	def.Translations = translations
+
+	entries[def.Code] = newEntry(def)
+	return nil
+}
+
+// newEntry prepares the language matcher of a definition, the default
+// language comes first so it wins when nothing matches
+func newEntry(def Definition) *catalogEntry {
+	tags := []language.Tag{DefaultLanguage}
+	for lang := range def.Translations {
+		tags = append(tags, language.MustParse(lang))
+	}
+	sort.Slice(tags[1:], func(i, j int) bool {
+		return tags[i+1].String() < tags[j+1].String()
+	})
+
+	return &catalogEntry{
+		def:     def,
+		tags:    tags,
+		matcher: language.NewMatcher(tags),
+	}
+}
+
+// Lookup returns the definition of a registered code
+func Lookup(code string) (Definition, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	entry, ok := catalog[code]
+	if !ok {
+		return Definition{}, false
+	}
+	return entry.def, true
+}
+
+// Definitions returns every registered definition sorted by code
+func Definitions() []Definition {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	defs := make([]Definition, 0, len(catalog))
+	for _, entry := range catalog {
+		defs = append(defs, entry.def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Code < defs[j].Code
+	})
+	return defs
+}
+
+// Localize renders the message of a code in the language that best matches
+// an Accept-Language header. lang is the tag of the translation used, empty
+// when the default message was used. ok is false for unknown codes.
+func Localize(code, acceptLanguage string, params Params) (message, lang string, ok bool) {
+	catalogMu.RLock()
+	entry, ok := catalog[code]
+	catalogMu.RUnlock()
+	if !ok {
+		return "", "", false
+	}
+
+	template := entry.def.Message
+	if acceptLanguage != "" && len(entry.tags) > 1 {
+		if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(tags) > 0 {
+			_, index, confidence := entry.matcher.Match(tags...)
+			if index > 0 && confidence != language.No {
+				lang = entry.tags[index].String()
+				template = entry.def.Translations[lang]
+			}
+		}
+	}
+
+	return renderMessage(template, params), lang, true
+}
+
+var placeholder = regexp.MustCompile(`\{(\w+)(?:\|([^}]*))?\}`)
+
+// renderMessage substitutes {name} and {name|default} placeholders
+func renderMessage(template string, params Params) string {
+	return placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		groups := placeholder.FindStringSubmatch(match)
+		if value, ok := params[groups[1]]; ok {
+			return fmt.Sprint(value)
+		}
+		return groups[2]
+	})
+}
+
+// FromCode creates an error from a registered code, rendering its message
+// template with params. Unknown codes become internal server errors.
+func FromCode(code string, params Params) *AppError {
+	message, _, ok := Localize(code, "", params)
+	if !ok {
+		message = MessageForCode(CodeInternalServerError)
+	}
+
+	err := build(StatusForCode(code), code, message, nil)
+	err.Params = params
+	err.localizable = ok
+	return err
+}
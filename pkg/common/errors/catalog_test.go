@@ -0,0 +1,98 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		params         Params
+		wantMessage    string
+		wantLang       string
+	}{
+		{"default", "", nil, "Resource not found", ""},
+		{"params", "", Params{"resource": "User"}, "User not found", ""},
+		{"translation", "id-ID,en;q=0.5", nil, "Data tidak ditemukan", "id"},
+		{"translation params", "id", Params{"resource": "Pengguna"}, "Pengguna tidak ditemukan", "id"},
+		{"unmatched", "fr", nil, "Resource not found", ""},
+		{"malformed", ";;;", nil, "Resource not found", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, lang, ok := Localize(CodeNotFound, tt.acceptLanguage, tt.params)
+			if !ok || message != tt.wantMessage || lang != tt.wantLang {
+				t.Errorf("Localize() = %q, %q, %v, want %q, %q, true", message, lang, ok, tt.wantMessage, tt.wantLang)
+			}
+		})
+	}
+
+	if _, _, ok := Localize("UNKNOWN_CODE", "", nil); ok {
+		t.Error("Localize() of an unknown code is ok")
+	}
+}
+
+func TestRenderMessage(t *testing.T) {
+	tests := []struct {
+		template string
+		params   Params
+		want     string
+	}{
+		{"plain", nil, "plain"},
+		{"{name} missing", nil, " missing"},
+		{"{name|Someone} here", nil, "Someone here"},
+		{"{name|Someone} here", Params{"name": "Ana"}, "Ana here"},
+		{"{count} items", Params{"count": 3}, "3 items"},
+	}
+
+	for _, tt := range tests {
+		if got := renderMessage(tt.template, tt.params); got != tt.want {
+			t.Errorf("renderMessage(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestFromCode(t *testing.T) {
+	err := FromCode(CodeNotFound, Params{"resource": "User"})
+	if err.StatusCode != http.StatusNotFound || err.Code != CodeNotFound || err.Message != "User not found" {
+		t.Errorf("FromCode() = %d %s %q", err.StatusCode, err.Code, err.Message)
+	}
+
+	unknown := FromCode("UNKNOWN_CODE", nil)
+	if unknown.StatusCode != http.StatusInternalServerError {
+		t.Errorf("FromCode() of an unknown code has status %d, want 500", unknown.StatusCode)
+	}
+}
+
+func TestBuiltinTranslations(t *testing.T) {
+	for _, def := range Definitions() {
+		if _, ok := def.Translations["id"]; !ok {
+			t.Errorf("code %s has no Indonesian message", def.Code)
+		}
+	}
+}
+
+func TestAddDefinition(t *testing.T) {
+	entries := newCatalog(nil)
+
+	tests := map[string]Definition{
+		"no code":   {Status: http.StatusBadRequest},
+		"status":    {Code: "SUCCESS", Status: http.StatusOK},
+		"language":  {Code: "BAD_LANGUAGE", Status: http.StatusBadRequest, Translations: map[string]string{"not a tag!": ""}},
+		"duplicate": {Code: "DUPLICATE", Status: http.StatusBadRequest},
+	}
+	if err := addDefinition(entries, tests["duplicate"]); err != nil {
+		t.Fatalf("addDefinition() error = %v", err)
+	}
+
+	for name, def := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := addDefinition(entries, def); err == nil {
+				t.Error("addDefinition() error = nil")
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync/atomic"
 )
 
 type AppError struct {
@@ -10,39 +15,245 @@ type AppError struct {
 	Message    string      `json:"message"`
 	Field      string      `json:"field,omitempty"`
 	Details    interface{} `json:"details,omitempty"`
+	// Err is the internal cause. It is logged with the request ID but never
+	// sent to clients.
+	Err error `json:"-"`
+	// Params are the values of the message template, kept to render the
+	// message in the client language
+	Params Params `json:"-"`
+
+	localizable bool
+	stack       []uintptr
 }
 
 func (e *AppError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
+// Unwrap returns the internal cause
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any AppError with the same code, so errors.Is(err, ErrNotFound)
+// holds for every not found error
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
 func New(statusCode int, code, message string) *AppError {
+	return build(statusCode, code, message, nil)
+}
+
+// build is called directly by every exported constructor so the captured
+// stack starts at the constructor's caller
+func build(statusCode int, code, message string, cause error) *AppError {
 	return &AppError{
 		StatusCode: statusCode,
 		Code:       code,
 		Message:    message,
+		Err:        cause,
+		stack:      callers(),
 	}
 }
 
+// Wrap attaches an internal cause to a new error with the given code. The
+// status and message are the defaults of the code; the cause message is
+// not exposed. Wrapping nil returns nil, which is why the result is an
+// error rather than an *AppError.
+func Wrap(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+
+	appErr := build(StatusForCode(code), code, MessageForCode(code), err)
+	appErr.localizable = true
+	return appErr
+}
+
+// WithField returns a copy of the error for the given input field
 func (e *AppError) WithField(field string) *AppError {
-	e.Field = field
-	return e
+	clone := *e
+	clone.Field = field
+	return &clone
 }
 
+// Localize returns the message in the language best matching an
+// Accept-Language header and the tag of that language, empty when the
+// message is not translated. Messages set by the caller are not localized.
+func (e *AppError) Localize(acceptLanguage string) (message, lang string) {
+	if !e.localizable {
+		return e.Message, ""
+	}
+
+	message, lang, ok := Localize(e.Code, acceptLanguage, e.Params)
+	if !ok {
+		return e.Message, ""
+	}
+	return message, lang
+}
+
+// WithDetails returns a copy of the error with extra details for clients
 func (e *AppError) WithDetails(details interface{}) *AppError {
-	e.Details = details
-	return e
+	clone := *e
+	clone.Details = details
+	return &clone
+}
+
+// WithCause returns a copy of the error with an internal cause
+func (e *AppError) WithCause(err error) *AppError {
+	clone := *e
+	clone.Err = err
+	return &clone
 }
 
-// Common error codes
+// Common error codes, registered in the catalog with their status and
+// default message
 const (
-	CodeBadRequest          = "BAD_REQUEST"
-	CodeUnauthorized        = "UNAUTHORIZED"
-	CodeForbidden           = "FORBIDDEN"
-	CodeNotFound            = "NOT_FOUND"
-	CodeConflict            = "CONFLICT"
-	CodeInternalServerError = "INTERNAL_SERVER_ERROR"
-	CodeValidationError     = "VALIDATION_ERROR"
-	CodeNotImplemented      = "NOT_IMPLEMENTED"
-	CodeTooManyRequests     = "TOO_MANY_REQUESTS"
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeNotFound             = "NOT_FOUND"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodeNotAcceptable        = "NOT_ACCEPTABLE"
+	CodeConflict             = "CONFLICT"
+	CodeInternalServerError  = "INTERNAL_SERVER_ERROR"
+	CodeValidationError      = "VALIDATION_ERROR"
+	CodeNotImplemented       = "NOT_IMPLEMENTED"
+	CodeTooManyRequests      = "TOO_MANY_REQUESTS"
+	CodePreconditionFailed   = "PRECONDITION_FAILED"
+	CodeServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	CodeTimeout              = "TIMEOUT"
+	CodeRequestCanceled      = "REQUEST_CANCELED"
+	CodeBuildInfoUnavailable = "BUILD_INFO_UNAVAILABLE"
+)
+
+// StatusForCode returns the HTTP status of a code, 500 for unknown codes
+func StatusForCode(code string) int {
+	if def, ok := Lookup(code); ok {
+		return def.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// MessageForCode returns the default client message of a code
+func MessageForCode(code string) string {
+	if message, _, ok := Localize(code, "", nil); ok {
+		return message
+	}
+	return MessageForCode(CodeInternalServerError)
+}
+
+// Sentinels for errors.Is, matching by code
+var (
+	ErrBadRequest      = &AppError{StatusCode: http.StatusBadRequest, Code: CodeBadRequest, Message: MessageForCode(CodeBadRequest)}
+	ErrUnauthorized    = &AppError{StatusCode: http.StatusUnauthorized, Code: CodeUnauthorized, Message: MessageForCode(CodeUnauthorized)}
+	ErrForbidden       = &AppError{StatusCode: http.StatusForbidden, Code: CodeForbidden, Message: MessageForCode(CodeForbidden)}
+	ErrNotFound        = &AppError{StatusCode: http.StatusNotFound, Code: CodeNotFound, Message: MessageForCode(CodeNotFound)}
+	ErrConflict        = &AppError{StatusCode: http.StatusConflict, Code: CodeConflict, Message: MessageForCode(CodeConflict)}
+	ErrInternal        = &AppError{StatusCode: http.StatusInternalServerError, Code: CodeInternalServerError, Message: MessageForCode(CodeInternalServerError)}
+	ErrValidation      = &AppError{StatusCode: http.StatusBadRequest, Code: CodeValidationError, Message: MessageForCode(CodeValidationError)}
+	ErrNotImplemented  = &AppError{StatusCode: http.StatusNotImplemented, Code: CodeNotImplemented, Message: MessageForCode(CodeNotImplemented)}
+	ErrTooManyRequests = &AppError{StatusCode: http.StatusTooManyRequests, Code: CodeTooManyRequests, Message: MessageForCode(CodeTooManyRequests)}
 )
+
+// NotFound reports a missing resource, e.g. NotFound("User", id)
+func NotFound(resource, id string) *AppError {
+	params := Params{"resource": resource}
+	message, _, _ := Localize(CodeNotFound, "", params)
+
+	err := build(http.StatusNotFound, CodeNotFound, message, nil)
+	err.Details = map[string]string{"resource": resource, "id": id}
+	err.Params = params
+	err.localizable = true
+	return err
+}
+
+// Conflict reports a duplicate or concurrently modified resource
+func Conflict(message string) *AppError {
+	return build(http.StatusConflict, CodeConflict, message, nil)
+}
+
+// BadRequest reports a malformed request
+func BadRequest(message string) *AppError {
+	return build(http.StatusBadRequest, CodeBadRequest, message, nil)
+}
+
+// Validation reports an invalid input field
+func Validation(field, message string) *AppError {
+	err := build(http.StatusBadRequest, CodeValidationError, message, nil)
+	err.Field = field
+	return err
+}
+
+// Unauthorized reports a missing or invalid authentication
+func Unauthorized(message string) *AppError {
+	return build(http.StatusUnauthorized, CodeUnauthorized, message, nil)
+}
+
+// Forbidden reports an authenticated caller lacking permissions
+func Forbidden(message string) *AppError {
+	return build(http.StatusForbidden, CodeForbidden, message, nil)
+}
+
+// Internal wraps an unexpected error, clients only see a generic message.
+// Wrapping nil returns nil.
+func Internal(err error) error {
+	if err == nil {
+		return nil
+	}
+	appErr := build(http.StatusInternalServerError, CodeInternalServerError, MessageForCode(CodeInternalServerError), err)
+	appErr.localizable = true
+	return appErr
+}
+
+// As returns the first AppError in err's chain
+func As(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+var captureStack atomic.Bool
+
+// SetCaptureStack enables recording the call stack when errors are created,
+// it is off by default because it costs an allocation per error
+func SetCaptureStack(enabled bool) {
+	captureStack.Store(enabled)
+}
+
+func callers() []uintptr {
+	if !captureStack.Load() {
+		return nil
+	}
+
+	pcs := make([]uintptr, 32)
+	// Skip runtime.Callers, callers, build and the constructor
+	n := runtime.Callers(4, pcs)
+	return pcs[:n]
+}
+
+// StackTrace returns the stack captured when the error was created, empty
+// unless SetCaptureStack(true) was called
+func (e *AppError) StackTrace() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
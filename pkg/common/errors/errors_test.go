@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	if Wrap(nil, CodeConflict) != nil {
+		t.Error("Wrap(nil) != nil")
+	}
+	if Internal(nil) != nil {
+		t.Error("Internal(nil) != nil")
+	}
+
+	cause := errors.New("duplicate key")
+	err := Wrap(cause, CodeConflict)
+
+	appErr, ok := As(fmt.Errorf("create: %w", err))
+	if !ok {
+		t.Fatalf("As() found no AppError in %v", err)
+	}
+	if appErr.StatusCode != http.StatusConflict || appErr.Message != MessageForCode(CodeConflict) {
+		t.Errorf("Wrap() = %d %q", appErr.StatusCode, appErr.Message)
+	}
+	if !errors.Is(err, cause) || !errors.Is(err, ErrConflict) || errors.Is(err, ErrNotFound) {
+		t.Error("Wrap() does not match its cause and code")
+	}
+	if !strings.Contains(err.Error(), "duplicate key") {
+		t.Errorf("Error() = %q, want the cause", err.Error())
+	}
+}
+
+func TestWithCause(t *testing.T) {
+	base := Conflict("taken")
+	cause := errors.New("duplicate key")
+
+	err := base.WithCause(cause).WithField("email").WithDetails("details")
+	if base.Err != nil || base.Field != "" || base.Details != nil {
+		t.Error("With methods modified the original error")
+	}
+	if err.Err != cause || err.Field != "email" || err.Details != "details" {
+		t.Errorf("With methods = %+v", err)
+	}
+}
+
+func TestLocalizeMessage(t *testing.T) {
+	if message, lang := NotFound("User", "42").Localize("id"); message != "User tidak ditemukan" || lang != "id" {
+		t.Errorf("Localize() = %q, %q", message, lang)
+	}
+
+	// Messages set by the caller are not translated
+	if message, lang := Conflict("Email taken").Localize("id"); message != "Email taken" || lang != "" {
+		t.Errorf("Localize() = %q, %q, want the caller message", message, lang)
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	if stack := BadRequest("off").StackTrace(); stack != "" {
+		t.Errorf("StackTrace() = %q with capture disabled", stack)
+	}
+
+	SetCaptureStack(true)
+	defer SetCaptureStack(false)
+
+	stack := BadRequest("on").StackTrace()
+	// The stack starts at the caller of the constructor
+	first, _, _ := strings.Cut(stack, "\n")
+	if !strings.HasSuffix(first, ".TestStackTrace") {
+		t.Errorf("StackTrace() starts with %q, want TestStackTrace", first)
+	}
+}
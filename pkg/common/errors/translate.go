@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"firebase.google.com/go/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StatusClientClosedRequest is the non-standard status used when the client
+// goes away before the response is written
+const StatusClientClosedRequest = 499
+
+// StatusText is http.StatusText with a text for StatusClientClosedRequest
+func StatusText(code int) string {
+	if code == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(code)
+}
+
+// Translator converts an infrastructure error to an AppError. It returns
+// nil when it does not recognize err.
+type Translator func(err error) *AppError
+
+var (
+	translatorsMu sync.RWMutex
+	translators   []Translator
+)
+
+// RegisterTranslator adds a translator that runs before the built-in ones,
+// e.g. for the errors of a third party client used by a module
+func RegisterTranslator(t Translator) {
+	translatorsMu.Lock()
+	defer translatorsMu.Unlock()
+
+	translators = append(translators, t)
+}
+
+// Translate maps context, gRPC (Firestore) and Firebase Auth errors to an
+// AppError with a stable code, keeping err as its internal cause. Errors
+// that already contain an AppError or are not recognized are returned as is.
+func Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := As(err); ok {
+		return err
+	}
+
+	translatorsMu.RLock()
+	registered := translators
+	translatorsMu.RUnlock()
+
+	for _, translate := range registered {
+		if appErr := translate(err); appErr != nil {
+			return appErr
+		}
+	}
+
+	for _, translate := range []Translator{translateContext, translateGRPC, translateAuth} {
+		if appErr := translate(err); appErr != nil {
+			return appErr
+		}
+	}
+
+	return err
+}
+
+func translateContext(err error) *AppError {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return FromCode(CodeRequestCanceled, nil).WithCause(err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return FromCode(CodeTimeout, nil).WithCause(err)
+	}
+	return nil
+}
+
+// grpcCodes leaves out FailedPrecondition and Unauthenticated. The former
+// also covers missing Firestore indexes, and the repository reports its
+// own precondition failures as a conflict. The latter is a failure of the
+// server credentials, not of the client. Both stay internal server errors.
+var grpcCodes = map[codes.Code]string{
+	codes.Canceled:          CodeRequestCanceled,
+	codes.NotFound:          CodeNotFound,
+	codes.AlreadyExists:     CodeConflict,
+	codes.Aborted:           CodeConflict,
+	codes.DeadlineExceeded:  CodeTimeout,
+	codes.Unavailable:       CodeServiceUnavailable,
+	codes.PermissionDenied:  CodeForbidden,
+	codes.ResourceExhausted: CodeTooManyRequests,
+	codes.Unimplemented:     CodeNotImplemented,
+}
+
+// translateGRPC maps the status of Firestore and other gRPC client errors
+func translateGRPC(err error) *AppError {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+
+	code, ok := grpcCodes[st.Code()]
+	if !ok {
+		return nil
+	}
+	return FromCode(code, nil).WithCause(err)
+}
+
+// translateAuth maps Firebase Auth user management errors. The auth
+// predicates do not unwrap, so every error of the chain is checked.
+func translateAuth(err error) *AppError {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch {
+		case auth.IsUserNotFound(e):
+			return FromCode(CodeNotFound, Params{"resource": "User"}).WithCause(err)
+		case auth.IsTenantNotFound(e):
+			return FromCode(CodeNotFound, Params{"resource": "Tenant"}).WithCause(err)
+		case auth.IsEmailAlreadyExists(e), auth.IsUIDAlreadyExists(e), auth.IsPhoneNumberAlreadyExists(e):
+			return FromCode(CodeConflict, nil).WithCause(err)
+		case auth.IsIDTokenRevoked(e), auth.IsSessionCookieRevoked(e), auth.IsTenantIDMismatch(e):
+			return FromCode(CodeUnauthorized, nil).WithCause(err)
+		case auth.IsInvalidEmail(e):
+			return FromCode(CodeValidationError, nil).WithField("email").WithCause(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), CodeRequestCanceled},
+		{"deadline", context.DeadlineExceeded, CodeTimeout},
+		{"not found", status.Error(codes.NotFound, "missing"), CodeNotFound},
+		{"aborted", status.Error(codes.Aborted, "contention"), CodeConflict},
+		{"unavailable", status.Error(codes.Unavailable, "down"), CodeServiceUnavailable},
+		{"permission", status.Error(codes.PermissionDenied, "rules"), CodeForbidden},
+		{"exhausted", status.Error(codes.ResourceExhausted, "quota"), CodeTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr, ok := As(Translate(tt.err))
+			if !ok || appErr.Code != tt.want {
+				t.Fatalf("Translate() = %v, want code %s", appErr, tt.want)
+			}
+			if !errors.Is(appErr, tt.err) {
+				t.Error("Translate() dropped the cause")
+			}
+		})
+	}
+}
+
+func TestTranslateUnmapped(t *testing.T) {
+	// Server side failures stay internal server errors
+	for _, err := range []error{
+		nil,
+		errors.New("plain"),
+		status.Error(codes.FailedPrecondition, "missing index"),
+		status.Error(codes.Unauthenticated, "bad credentials"),
+		status.Error(codes.Internal, "boom"),
+	} {
+		if got := Translate(err); got != err {
+			t.Errorf("Translate(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
+
+func TestTranslateKeepsAppError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", New(http.StatusConflict, CodeConflict, "taken"))
+	if got := Translate(err); got != err {
+		t.Errorf("Translate() = %v, want it unchanged", got)
+	}
+}
@@ -0,0 +1,23 @@
+package errors
+
+// Indonesian messages of the built-in codes
+func init() {
+	RegisterTranslations("id", map[string]string{
+		CodeBadRequest:           "Permintaan tidak valid",
+		CodeUnauthorized:         "Akses tidak sah",
+		CodeForbidden:            "Akses ditolak",
+		CodeNotFound:             "{resource|Data} tidak ditemukan",
+		CodeMethodNotAllowed:     "Metode tidak diizinkan",
+		CodeNotAcceptable:        "Tidak ada tipe media yang diterima yang dapat menampilkan respons",
+		CodeConflict:             "Data sudah ada atau telah diubah",
+		CodeInternalServerError:  "Terjadi kesalahan yang tidak terduga",
+		CodeValidationError:      "Validasi gagal",
+		CodeNotImplemented:       "Belum diimplementasikan",
+		CodeTooManyRequests:      "Batas jumlah permintaan terlampaui",
+		CodePreconditionFailed:   "Prasyarat tidak terpenuhi",
+		CodeServiceUnavailable:   "Layanan sedang tidak tersedia",
+		CodeTimeout:              "Waktu permintaan habis",
+		CodeRequestCanceled:      "Permintaan dibatalkan oleh klien",
+		CodeBuildInfoUnavailable: "Informasi build tidak tersedia",
+	})
+}
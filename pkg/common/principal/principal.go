@@ -0,0 +1,20 @@
+package principal
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated user ID
+func NewContext(ctx context.Context, uid string) context.Context {
+	return context.WithValue(ctx, contextKey{}, uid)
+}
+
+// FromContext returns the authenticated user ID stored in ctx, or an empty
+// string for anonymous requests and background work
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	uid, _ := ctx.Value(contextKey{}).(string)
+	return uid
+}
@@ -0,0 +1,54 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header carries the request ID between services
+const Header = "X-Request-ID"
+
+// MaxLength is the longest request ID accepted from clients
+const MaxLength = 128
+
+type contextKey struct{}
+
+// New generates a request ID
+func New() string {
+	return uuid.New().String()
+}
+
+// Valid reports whether a client supplied request ID is safe to reuse:
+// non-empty, at most MaxLength characters of letters, digits, '-', '_',
+// '.' or ':'
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,49 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"3fa85f64-5717-4562-b3fc-2c963f66afa6", true},
+		{"trace_01.span:02", true},
+		{strings.Repeat("a", MaxLength), true},
+		{"", false},
+		{strings.Repeat("a", MaxLength+1), false},
+		{"with space", false},
+		{"line\nbreak", false},
+		{"quote\"", false},
+		{"ünicode", false},
+	}
+
+	for _, tt := range tests {
+		if got := Valid(tt.id); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestNewIsValid(t *testing.T) {
+	if id := New(); !Valid(id) {
+		t.Errorf("New() = %q, want a valid request ID", id)
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("FromContext() = %q, want empty", got)
+	}
+	//nolint:staticcheck // a nil context is accepted on purpose
+	if got := FromContext(nil); got != "" {
+		t.Errorf("FromContext(nil) = %q, want empty", got)
+	}
+	if got := FromContext(NewContext(context.Background(), "req-1")); got != "req-1" {
+		t.Errorf("FromContext() = %q, want req-1", got)
+	}
+}
@@ -0,0 +1,422 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	stderrors "errors"
+	"fmt"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang-template/pkg/common/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// Media types of the built-in encoders
+const (
+	MediaTypeJSON    = "application/json"
+	MediaTypeXML     = "application/xml"
+	MediaTypeYAML    = "application/yaml"
+	MediaTypeMsgPack = "application/msgpack"
+	MediaTypeCSV     = "text/csv"
+)
+
+// ErrNotEncodable is returned by an Encoder that cannot represent a body,
+// e.g. CSV for a single object, so the next acceptable media type is tried
+var ErrNotEncodable = stderrors.New("response: body cannot be encoded in this media type")
+
+// Encoder marshals a response body in one media type
+type Encoder func(body Response) ([]byte, error)
+
+type encoderEntry struct {
+	mediaType   string
+	contentType string
+	encode      Encoder
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   []encoderEntry
+)
+
+func init() {
+	RegisterEncoder(MediaTypeJSON, encodeJSON)
+	RegisterEncoder(MediaTypeXML, encodeXML)
+	RegisterEncoder("text/xml", encodeXML)
+	RegisterEncoder(MediaTypeYAML, encodeYAML)
+	RegisterEncoder("application/x-yaml", encodeYAML)
+	RegisterEncoder("text/yaml", encodeYAML)
+	RegisterEncoder(MediaTypeMsgPack, encodeMsgPack)
+	RegisterEncoder("application/x-msgpack", encodeMsgPack)
+	RegisterEncoder(MediaTypeCSV, encodeCSV)
+}
+
+// RegisterEncoder adds or replaces the encoder of a media type. Encoders
+// are tried in registration order when the client accepts several types,
+// JSON comes first and is used when there is no Accept header.
+func RegisterEncoder(mediaType string, enc Encoder) {
+	mediaType = strings.ToLower(mediaType)
+	contentType := mediaType
+	if strings.HasPrefix(mediaType, "text/") || mediaType == MediaTypeJSON || strings.HasSuffix(mediaType, "xml") || strings.HasSuffix(mediaType, "yaml") {
+		contentType = mime.FormatMediaType(mediaType, map[string]string{"charset": "utf-8"})
+	}
+
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	entry := encoderEntry{mediaType: mediaType, contentType: contentType, encode: enc}
+	for i := range encoders {
+		if encoders[i].mediaType == mediaType {
+			encoders[i] = entry
+			return
+		}
+	}
+	encoders = append(encoders, entry)
+}
+
+// render writes body in the first media type accepted by the client that
+// can represent it, or a 406 error when there is none
+func render(c *gin.Context, statusCode int, body Response) {
+	// The representation depends on the Accept header
+	addVary(c, "Accept")
+
+	encodersMu.RLock()
+	available := encoders
+	encodersMu.RUnlock()
+
+	accepted := acceptedMediaTypes(c.GetHeader("Accept"))
+	if len(accepted) == 0 {
+		accepted = []acceptedMediaType{{mediaType: "*/*", q: 1}}
+	}
+
+	for _, accept := range accepted {
+		if accept.q == 0 {
+			continue
+		}
+		for _, enc := range available {
+			if !accept.matches(enc.mediaType) || excluded(accepted, enc.mediaType) {
+				continue
+			}
+
+			data, err := enc.encode(body)
+			if stderrors.Is(err, ErrNotEncodable) {
+				continue
+			}
+			if err != nil {
+				Error(c, errors.Internal(fmt.Errorf("encode %s response: %w", enc.mediaType, err)))
+				return
+			}
+
+			c.Data(statusCode, enc.contentType, data)
+			return
+		}
+	}
+
+	Error(c, errors.FromCode(errors.CodeNotAcceptable, nil))
+}
+
+// acceptedMediaType is one media range of an Accept header
+type acceptedMediaType struct {
+	mediaType string
+	q         float64
+}
+
+// matches reports whether the range covers mediaType, e.g. text/* covers
+// text/csv
+func (a acceptedMediaType) matches(mediaType string) bool {
+	if a.mediaType == "*/*" || a.mediaType == mediaType {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(a.mediaType, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
+
+func (a acceptedMediaType) wildcard() bool {
+	return strings.HasSuffix(a.mediaType, "/*")
+}
+
+// acceptedMediaTypes parses an Accept header, most preferred first. Ranges
+// with the same quality keep their order, specific types before wildcards.
+func acceptedMediaTypes(header string) []acceptedMediaType {
+	var accepted []acceptedMediaType
+	for _, value := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted = append(accepted, acceptedMediaType{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool {
+		if accepted[i].q != accepted[j].q {
+			return accepted[i].q > accepted[j].q
+		}
+		return !accepted[i].wildcard() && accepted[j].wildcard()
+	})
+	return accepted
+}
+
+// addVary adds a request header to Vary once
+func addVary(c *gin.Context, header string) {
+	for _, value := range c.Writer.Header().Values("Vary") {
+		for _, existing := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(existing), header) {
+				return
+			}
+		}
+	}
+	c.Writer.Header().Add("Vary", header)
+}
+
+// excluded reports whether the client explicitly refused mediaType with q=0
+func excluded(accepted []acceptedMediaType, mediaType string) bool {
+	for _, accept := range accepted {
+		if accept.q == 0 && accept.mediaType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeJSON(body Response) ([]byte, error) {
+	return json.Marshal(body)
+}
+
+// encodeXML goes through JSON so element names follow the json tags like
+// the other formats. The root element is <response>, array items are
+// <item> elements and keys that are not XML names become
+// <entry key="...">.
+func encodeXML(body Response) ([]byte, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := writeXMLElement(enc, dec, "response"); err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeXMLElement writes the next JSON value of dec as an element, null
+// becomes an empty element
+func writeXMLElement(enc *xml.Encoder, dec *json.Decoder, name string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	start := xmlStartElement(name)
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		for dec.More() {
+			child := "item"
+			if value == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child = key.(string)
+			}
+			if err := writeXMLElement(enc, dec, child); err != nil {
+				return err
+			}
+		}
+		// Closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(value))); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+func xmlStartElement(name string) xml.StartElement {
+	if validXMLName(name) {
+		return xml.StartElement{Name: xml.Name{Local: name}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+	}
+}
+
+// validXMLName accepts ASCII names, the xml prefix is reserved
+func validXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case i > 0 && (r >= '0' && r <= '9' || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// encodeYAML goes through JSON so field names follow the json tags
+func encodeYAML(body Response) ([]byte, error) {
+	generic, err := toGeneric(body)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(generic)
+}
+
+func encodeMsgPack(body Response) ([]byte, error) {
+	var buf bytes.Buffer
+	// The msgpack handle uses the json tags when there is no codec tag
+	err := codec.NewEncoder(&buf, new(codec.MsgpackHandle)).Encode(body)
+	return buf.Bytes(), err
+}
+
+// encodeCSV writes list data as one row per item with a header of the
+// item fields, nested values are written as JSON
+func encodeCSV(body Response) ([]byte, error) {
+	if body.Data == nil {
+		return nil, ErrNotEncodable
+	}
+	kind := reflect.Indirect(reflect.ValueOf(body.Data)).Kind()
+	if kind != reflect.Slice && kind != reflect.Array {
+		return nil, ErrNotEncodable
+	}
+
+	raw, err := json.Marshal(body.Data)
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		// e.g. a []byte, which marshals to a base64 string
+		return nil, ErrNotEncodable
+	}
+
+	// Columns in field order of the first item, later fields appended
+	var columns []string
+	seen := make(map[string]bool)
+	rows := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		keys, err := objectKeys(item)
+		if err != nil {
+			return nil, ErrNotEncodable
+		}
+		for _, key := range keys {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(item, &row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = csvValue(row[column])
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// objectKeys returns the keys of a JSON object in document order
+func objectKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, ErrNotEncodable
+	}
+
+	var keys []string
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, token.(string))
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// csvValue writes strings unquoted, null as empty and anything else as JSON.
+// Strings that a spreadsheet would run as a formula are prefixed with a
+// quote.
+func csvValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+			return "'" + s
+		}
+		return s
+	}
+	return string(raw)
+}
+
+// toGeneric converts a value to maps, slices and scalars through JSON
+func toGeneric(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(raw, &generic)
+	return generic, err
+}
@@ -0,0 +1,177 @@
+package response
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"reflect"
+	"testing"
+)
+
+func TestAcceptedMediaTypes(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []acceptedMediaType
+	}{
+		{header: "", want: nil},
+		{
+			header: "text/*, application/xml;q=0.9, text/csv",
+			want: []acceptedMediaType{
+				{mediaType: "text/csv", q: 1},
+				{mediaType: "text/*", q: 1},
+				{mediaType: "application/xml", q: 0.9},
+			},
+		},
+		{
+			header: "application/yaml;q=0.5, invalid;;, application/json;q=0",
+			want: []acceptedMediaType{
+				{mediaType: "application/yaml", q: 0.5},
+				{mediaType: "application/json", q: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := acceptedMediaTypes(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("acceptedMediaTypes(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptedMediaTypeMatches(t *testing.T) {
+	tests := []struct {
+		accept    string
+		mediaType string
+		want      bool
+	}{
+		{accept: "*/*", mediaType: "text/csv", want: true},
+		{accept: "text/*", mediaType: "text/csv", want: true},
+		{accept: "text/*", mediaType: "application/json", want: false},
+		{accept: "application/json", mediaType: "application/json", want: true},
+		{accept: "application/json", mediaType: "application/xml", want: false},
+	}
+
+	for _, tt := range tests {
+		got := acceptedMediaType{mediaType: tt.accept, q: 1}.matches(tt.mediaType)
+		if got != tt.want {
+			t.Errorf("%s matches %s = %v, want %v", tt.accept, tt.mediaType, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeXML(t *testing.T) {
+	body := Response{
+		Success: true,
+		Data: []map[string]interface{}{
+			{"id": "a", "2fa": true, "note": nil},
+		},
+	}
+
+	got, err := encodeXML(body)
+	if err != nil {
+		t.Fatalf("encodeXML() error = %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<response><success>true</success><data><item>` +
+		`<entry key="2fa">true</entry><id>a</id><note></note>` +
+		`</item></data></response>`
+	if string(got) != want {
+		t.Errorf("encodeXML() = %s, want %s", got, want)
+	}
+}
+
+func TestValidXMLName(t *testing.T) {
+	tests := map[string]bool{
+		"name":      true,
+		"_id":       true,
+		"created-1": true,
+		"":          false,
+		"1st":       false,
+		"xmlData":   false,
+		"a b":       false,
+	}
+
+	for name, want := range tests {
+		if got := validXMLName(name); got != want {
+			t.Errorf("validXMLName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestEncodeYAML(t *testing.T) {
+	got, err := encodeYAML(Response{Success: true, Meta: MetaData{Total: 1, Count: 1}})
+	if err != nil {
+		t.Fatalf("encodeYAML() error = %v", err)
+	}
+	want := "meta:\n    count: 1\n    currentPage: 0\n    perPage: 0\n    total: 1\n    totalPages: 0\nsuccess: true\n"
+	if string(got) != want {
+		t.Errorf("encodeYAML() = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeCSV(t *testing.T) {
+	type user struct {
+		ID    string   `json:"id"`
+		Name  string   `json:"name"`
+		Tags  []string `json:"tags,omitempty"`
+		Score int      `json:"score"`
+	}
+
+	body := Response{Data: []user{
+		{ID: "1", Name: "=HYPERLINK(\"x\")", Score: -1},
+		{ID: "2", Name: "Bob, Jr.", Tags: []string{"a"}},
+	}}
+	got, err := encodeCSV(body)
+	if err != nil {
+		t.Fatalf("encodeCSV() error = %v", err)
+	}
+	want := "id,name,score,tags\n" +
+		"1,\"'=HYPERLINK(\"\"x\"\")\",-1,\n" +
+		"2,\"Bob, Jr.\",0,\"[\"\"a\"\"]\"\n"
+	if string(got) != want {
+		t.Errorf("encodeCSV() = %q, want %q", got, want)
+	}
+}
+
+func TestEncodeCSVNotEncodable(t *testing.T) {
+	tests := map[string]interface{}{
+		"nil":     nil,
+		"object":  map[string]string{"id": "1"},
+		"bytes":   []byte("abc"),
+		"scalars": []int{1, 2},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := encodeCSV(Response{Data: data}); !stderrors.Is(err, ErrNotEncodable) {
+				t.Errorf("encodeCSV() error = %v, want ErrNotEncodable", err)
+			}
+		})
+	}
+}
+
+func TestCSVValue(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: `null`, want: ""},
+		{raw: `"plain"`, want: "plain"},
+		{raw: `""`, want: ""},
+		{raw: `"=1+1"`, want: "'=1+1"},
+		{raw: `"+1"`, want: "'+1"},
+		{raw: `"-1"`, want: "'-1"},
+		{raw: `"@SUM(A1)"`, want: "'@SUM(A1)"},
+		{raw: `"\tx"`, want: "'\tx"},
+		{raw: `"\rx"`, want: "'\rx"},
+		{raw: `-1`, want: "-1"},
+		{raw: `{"a":1}`, want: `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		if got := csvValue(json.RawMessage(tt.raw)); got != tt.want {
+			t.Errorf("csvValue(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
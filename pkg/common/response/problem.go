@@ -0,0 +1,183 @@
+package response
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-template/pkg/common/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error formats accepted by Options.ErrorFormat
+const (
+	// ErrorFormatEnvelope renders {success, error, meta}, unless the client
+	// asks for application/problem+json
+	ErrorFormatEnvelope = "envelope"
+	// ErrorFormatProblem always renders RFC 7807 problem details
+	ErrorFormatProblem = "problem"
+)
+
+// ProblemContentType is the media type of RFC 7807 responses
+const ProblemContentType = "application/problem+json"
+
+// Options configures how responses are rendered
+type Options struct {
+	// ErrorFormat is envelope (default) or problem
+	ErrorFormat string
+	// ProblemTypeBaseURL prefixes the problem type, e.g.
+	// https://docs.example.com/errors/ gives .../errors/not-found. Empty
+	// uses about:blank.
+	ProblemTypeBaseURL string
+	// StreamWriteTimeout is the deadline of each write of a streamed
+	// response, zero keeps the server write timeout for the whole stream
+	StreamWriteTimeout time.Duration
+	// Logger returns the request logger used for error and stream logs,
+	// nothing is logged when nil
+	Logger func(ctx context.Context) Logger
+}
+
+// Logger receives the logs of the response package, logger.Logger
+// implements it
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// requestLogger returns the logger configured for the request of ctx
+func requestLogger(ctx context.Context) Logger {
+	if newLogger := currentOptions().Logger; newLogger != nil {
+		return newLogger(ctx)
+	}
+	return nopLogger{}
+}
+
+var (
+	optionsMu sync.RWMutex
+	options   = Options{ErrorFormat: ErrorFormatEnvelope}
+)
+
+// Configure sets the rendering options, call it once at startup
+func Configure(opts Options) error {
+	switch opts.ErrorFormat {
+	case "":
+		opts.ErrorFormat = ErrorFormatEnvelope
+	case ErrorFormatEnvelope, ErrorFormatProblem:
+	default:
+		return fmt.Errorf("unknown error format %q", opts.ErrorFormat)
+	}
+
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	options = opts
+	return nil
+}
+
+func currentOptions() Options {
+	optionsMu.RLock()
+	defer optionsMu.RUnlock()
+	return options
+}
+
+// Problem is an RFC 7807 problem details object with our error code, field
+// errors and request ID as extension members
+type Problem struct {
+	Type      string       `json:"type" example:"about:blank"`
+	Title     string       `json:"title" example:"Not Found"`
+	Status    int          `json:"status" example:"404"`
+	Detail    string       `json:"detail,omitempty" example:"User not found"`
+	Instance  string       `json:"instance,omitempty" example:"/api/users/42"`
+	Code      string       `json:"code" example:"NOT_FOUND"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	Details   interface{}  `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+}
+
+// FieldError describes an invalid input field
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Message string `json:"message" example:"email is required"`
+}
+
+// wantsProblem reports whether the error should be rendered as problem
+// details, either by configuration or because the client prefers it
+func wantsProblem(c *gin.Context, opts Options) bool {
+	if opts.ErrorFormat == ErrorFormatProblem {
+		return true
+	}
+
+	// The representation depends on the Accept header
+	addVary(c, "Accept")
+
+	for _, accepted := range acceptedMediaTypes(c.GetHeader("Accept")) {
+		if accepted.mediaType == ProblemContentType && accepted.q > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// newProblem converts an error detail to problem details
+func newProblem(c *gin.Context, statusCode int, detail ErrorDetail, requestID string, opts Options) Problem {
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     errors.StatusText(statusCode),
+		Status:    statusCode,
+		Detail:    detail.Message,
+		Instance:  c.Request.URL.Path,
+		Code:      detail.Code,
+		RequestID: requestID,
+	}
+
+	if detail.Code != "" {
+		problem.Type = problemType(detail.Code, opts)
+	}
+
+	switch details := detail.Details.(type) {
+	case []FieldError:
+		problem.Errors = details
+	case nil:
+	default:
+		problem.Details = details
+	}
+
+	if detail.Field != "" {
+		problem.Errors = append([]FieldError{{Field: detail.Field, Message: detail.Message}}, problem.Errors...)
+	}
+
+	return problem
+}
+
+// ProblemType returns the documentation URL of an error code: its
+// catalog DocsURL, else ERROR_TYPE_BASE_URL followed by the code in kebab
+// case, else about:blank
+func ProblemType(code string) string {
+	return problemType(code, currentOptions())
+}
+
+func problemType(code string, opts Options) string {
+	if def, ok := errors.Lookup(code); ok && def.DocsURL != "" {
+		return def.DocsURL
+	}
+	if opts.ProblemTypeBaseURL != "" {
+		return strings.TrimSuffix(opts.ProblemTypeBaseURL, "/") + "/" + problemSlug(code)
+	}
+	return "about:blank"
+}
+
+// problemSlug turns NOT_FOUND into not-found
+func problemSlug(code string) string {
+	return strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
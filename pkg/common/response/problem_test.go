@@ -0,0 +1,108 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang-template/pkg/common/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestContext(accept string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/users/42", nil)
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	return c
+}
+
+func TestWantsProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		accept string
+		want   bool
+	}{
+		{"envelope", ErrorFormatEnvelope, "application/json", false},
+		{"configured", ErrorFormatProblem, "application/json", true},
+		{"requested", ErrorFormatEnvelope, "application/problem+json, application/json;q=0.5", true},
+		{"refused", ErrorFormatEnvelope, "application/problem+json;q=0", false},
+		{"wildcard", ErrorFormatEnvelope, "*/*", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wantsProblem(newTestContext(tt.accept), Options{ErrorFormat: tt.format}); got != tt.want {
+				t.Errorf("wantsProblem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	detail := ErrorDetail{
+		Code:    errors.CodeValidationError,
+		Message: "email is required",
+		Field:   "email",
+		Details: []FieldError{{Field: "name", Message: "name is too long"}},
+	}
+
+	got := newProblem(newTestContext(""), http.StatusBadRequest, detail, "req-1", Options{
+		ProblemTypeBaseURL: "https://docs.example.com/errors/",
+	})
+
+	want := Problem{
+		Type:     "https://docs.example.com/errors/validation-error",
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "email is required",
+		Instance: "/api/users/42",
+		Code:     errors.CodeValidationError,
+		Errors: []FieldError{
+			{Field: "email", Message: "email is required"},
+			{Field: "name", Message: "name is too long"},
+		},
+		RequestID: "req-1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newProblem() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewProblemDetails(t *testing.T) {
+	detail := ErrorDetail{Code: errors.CodeConflict, Details: map[string]string{"id": "42"}}
+
+	got := newProblem(newTestContext(""), http.StatusConflict, detail, "", Options{})
+	if got.Type != "about:blank" || got.Errors != nil || !reflect.DeepEqual(got.Details, detail.Details) {
+		t.Errorf("newProblem() = %+v", got)
+	}
+}
+
+func TestProblemTitle(t *testing.T) {
+	got := newProblem(newTestContext(""), errors.StatusClientClosedRequest, ErrorDetail{}, "", Options{})
+	if got.Title != "Client Closed Request" {
+		t.Errorf("Title = %q, want Client Closed Request", got.Title)
+	}
+}
+
+func TestProblemSlug(t *testing.T) {
+	if got := problemSlug("NOT_FOUND"); got != "not-found" {
+		t.Errorf("problemSlug() = %q, want not-found", got)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	defer func() { _ = Configure(Options{}) }()
+
+	if err := Configure(Options{ErrorFormat: "xml"}); err == nil {
+		t.Error("Configure() accepted an unknown error format")
+	}
+	if err := Configure(Options{}); err != nil || currentOptions().ErrorFormat != ErrorFormatEnvelope {
+		t.Errorf("Configure() = %v, format %q, want envelope", err, currentOptions().ErrorFormat)
+	}
+}
@@ -2,8 +2,11 @@ package response
 
 import (
 	"net/http"
+	"reflect"
 
+	"golang-template/pkg/common/conditional"
 	"golang-template/pkg/common/errors"
+	"golang-template/pkg/common/requestid"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,6 +25,11 @@ type ErrorDetail struct {
 	Details interface{} `json:"details,omitempty"`
 }
 
+// ErrorMeta is attached to every error response
+type ErrorMeta struct {
+	RequestID string `json:"requestId,omitempty" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+}
+
 type MetaData struct {
 	Total       int64 `json:"total"`
 	Count       int   `json:"count"`
@@ -32,73 +40,168 @@ type MetaData struct {
 	PrevPage    *int  `json:"prevPage,omitempty"`
 }
 
-// Success sends a successful response
+// Success sends a successful response in the media type negotiated with
+// the Accept header. Entities get their validators unless the handler set
+// an ETag, so the ETag a client reads is the one If-Match is checked with.
 func Success(c *gin.Context, statusCode int, data interface{}) {
-	c.JSON(statusCode, Response{
+	if e, ok := data.(conditional.Updated); ok && !isNil(data) && c.Writer.Header().Get("ETag") == "" {
+		SetValidators(c, e)
+	}
+
+	render(c, statusCode, Response{
 		Success: true,
 		Data:    data,
 	})
 }
 
-// sends an error response
+// Error sends an error response for the first AppError in err's chain,
+// anything else becomes a generic 500. Context, Firestore and Firebase Auth
+// errors are translated to AppErrors first. Messages of catalog codes are
+// localized with Accept-Language. Internal causes are logged with the
+// request ID and never included in the response.
 func Error(c *gin.Context, err error) {
+	statusCode, errorResponse := resolveError(c, err)
+	writeError(c, statusCode, errorResponse)
+}
+
+// resolveError logs err and returns the status and the localized detail
+// sent to the client
+func resolveError(c *gin.Context, err error) (int, ErrorDetail) {
+	err = errors.Translate(err)
+
 	var statusCode int
-	var errorResponse interface{}
+	var errorResponse ErrorDetail
+	var lang string
+
+	acceptLanguage := c.GetHeader("Accept-Language")
+
+	appErr, ok := errors.As(err)
+	if ok {
+		var message string
+		message, lang = appErr.Localize(acceptLanguage)
 
-	if appErr, ok := err.(*errors.AppError); ok {
 		statusCode = appErr.StatusCode
 		errorResponse = ErrorDetail{
 			Code:    appErr.Code,
-			Message: appErr.Message,
+			Message: message,
 			Field:   appErr.Field,
 			Details: appErr.Details,
 		}
 	} else {
+		var message string
+		message, lang, _ = errors.Localize(errors.CodeInternalServerError, acceptLanguage, nil)
+
 		statusCode = http.StatusInternalServerError
 		errorResponse = ErrorDetail{
-			Code:    "INTERNAL_SERVER_ERROR",
-			Message: "An unexpected error occurred",
+			Code:    errors.CodeInternalServerError,
+			Message: message,
 		}
 	}
 
-	c.JSON(statusCode, Response{
-		Success: false,
-		Error:   errorResponse,
-	})
+	// The message depends on the Accept-Language header
+	addVary(c, "Accept-Language")
+	if lang != "" {
+		c.Header("Content-Language", lang)
+	}
+
+	logError(c, statusCode, errorResponse.Code, err, appErr)
+	return statusCode, errorResponse
+}
+
+// logError records server errors and the internal cause of client errors
+// through the request logger, which carries the request ID. Server errors
+// are also attached to the gin context, e.g. for the tracing middleware.
+func logError(c *gin.Context, statusCode int, code string, err error, appErr *errors.AppError) {
+	ctx := c.Request.Context()
+	serverError := statusCode >= http.StatusInternalServerError
+
+	if !serverError && (appErr == nil || appErr.Err == nil) {
+		return
+	}
+
+	fields := []interface{}{"status", statusCode, "code", code, "error", err.Error()}
+	if appErr != nil {
+		if stack := appErr.StackTrace(); stack != "" {
+			fields = append(fields, "stack", stack)
+		}
+	}
+
+	log := requestLogger(ctx)
+	if serverError {
+		_ = c.Error(err)
+		log.Error("Request failed", fields...)
+		return
+	}
+	log.Info("Request rejected", fields...)
 }
 
 // ErrorWithCode sends an error response with a custom status code
 func ErrorWithCode(c *gin.Context, statusCode int, code, message string) {
-	c.JSON(statusCode, Response{
-		Success: false,
-		Error: ErrorDetail{
-			Code:    code,
-			Message: message,
-		},
+	writeError(c, statusCode, ErrorDetail{
+		Code:    code,
+		Message: message,
 	})
 }
 
 // ValidationError sends a validation error response
 func ValidationError(c *gin.Context, field, message string) {
-	c.JSON(http.StatusBadRequest, Response{
+	writeError(c, http.StatusBadRequest, ErrorDetail{
+		Code:    errors.CodeValidationError,
+		Message: message,
+		Field:   field,
+	})
+}
+
+// writeError sends the error envelope with the request ID in its metadata,
+// or RFC 7807 problem details when configured or requested by the client
+func writeError(c *gin.Context, statusCode int, detail ErrorDetail) {
+	requestID := requestid.FromContext(c.Request.Context())
+
+	// Validators set for a successful response, e.g. before negotiation
+	// failed, do not describe the error
+	c.Writer.Header().Del("ETag")
+	c.Writer.Header().Del("Last-Modified")
+
+	if opts := currentOptions(); wantsProblem(c, opts) {
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(statusCode, newProblem(c, statusCode, detail, requestID, opts))
+		return
+	}
+
+	c.JSON(statusCode, Response{
 		Success: false,
-		Error: ErrorDetail{
-			Code:    errors.CodeValidationError,
-			Message: message,
-			Field:   field,
+		Error:   detail,
+		Meta: ErrorMeta{
+			RequestID: requestID,
 		},
 	})
 }
 
-// WithMeta sends a successful response with metadata
+// WithMeta sends a successful response with metadata in the negotiated
+// media type, CSV only contains the data
 func WithMeta(c *gin.Context, statusCode int, data interface{}, meta MetaData) {
-	c.JSON(statusCode, Response{
+	render(c, statusCode, Response{
 		Success: true,
 		Data:    data,
 		Meta:    meta,
 	})
 }
 
+// SetValidators sets the ETag and Last-Modified headers of an entity, call
+// it before sending the entity so conditional GETs can be answered with
+// 304 Not Modified
+func SetValidators(c *gin.Context, e conditional.Updated) {
+	c.Header("ETag", conditional.EntityTag(e))
+	if updatedAt := e.GetUpdatedAt(); !updatedAt.IsZero() {
+		c.Header("Last-Modified", updatedAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+func isNil(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
 // 200 OK response
 func OK(c *gin.Context, data interface{}) {
 	Success(c, http.StatusOK, data)
@@ -0,0 +1,310 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang-template/pkg/common/errors"
+	"golang-template/pkg/common/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Media types of streamed lists
+const (
+	MediaTypeNDJSON      = "application/x-ndjson"
+	MediaTypeEventStream = "text/event-stream"
+)
+
+// streamFlushInterval bounds how long written items may wait in the
+// server buffer, also while the source is producing the next item
+const streamFlushInterval = 100 * time.Millisecond
+
+// Next returns the next item of a stream and io.EOF after the last one,
+// e.g. the Next method of an interfaces.Iterator
+type Next[T any] func() (T, error)
+
+// FromChannel adapts a channel to Next. The stream ends when ch is closed
+// or fails with the context error when ctx is done.
+func FromChannel[T any](ctx context.Context, ch <-chan T) Next[T] {
+	return func() (T, error) {
+		var zero T
+		select {
+		case item, ok := <-ch:
+			if !ok {
+				return zero, io.EOF
+			}
+			return item, nil
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Stream writes the items as NDJSON, server-sent events or a JSON array in
+// the standard envelope, depending on the Accept header
+func Stream[T any](c *gin.Context, next Next[T]) {
+	addVary(c, "Accept")
+
+	accepted := acceptedMediaTypes(c.GetHeader("Accept"))
+	if len(accepted) == 0 {
+		StreamJSONArray(c, next)
+		return
+	}
+
+	for _, accept := range accepted {
+		if accept.q == 0 {
+			continue
+		}
+		switch {
+		case accept.matches(MediaTypeJSON) && !excluded(accepted, MediaTypeJSON):
+			StreamJSONArray(c, next)
+			return
+		case accept.matches(MediaTypeNDJSON) && !excluded(accepted, MediaTypeNDJSON):
+			StreamNDJSON(c, next)
+			return
+		case accept.matches(MediaTypeEventStream) && !excluded(accepted, MediaTypeEventStream):
+			StreamSSE(c, next)
+			return
+		}
+	}
+
+	Error(c, errors.FromCode(errors.CodeNotAcceptable, nil))
+}
+
+// StreamNDJSON writes one JSON item per line. A failure after the first
+// item is written as a last line holding the error.
+func StreamNDJSON[T any](c *gin.Context, next Next[T]) {
+	stream(c, next, streamFormat{
+		contentType: MediaTypeNDJSON,
+		item: func(w io.Writer, _ int, data []byte) error {
+			_, err := fmt.Fprintf(w, "%s\n", data)
+			return err
+		},
+		fail: func(w io.Writer, failure []byte) error {
+			_, err := fmt.Fprintf(w, "%s\n", failure)
+			return err
+		},
+	})
+}
+
+// StreamJSONArray writes {"data":[...],"success":true}. The success flag
+// comes last so a failure after the first item can still be reported as
+// "success":false with the error.
+func StreamJSONArray[T any](c *gin.Context, next Next[T]) {
+	stream(c, next, streamFormat{
+		contentType: MediaTypeJSON + "; charset=utf-8",
+		begin: func(w io.Writer) error {
+			_, err := io.WriteString(w, `{"data":[`)
+			return err
+		},
+		item: func(w io.Writer, index int, data []byte) error {
+			if index > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			_, err := w.Write(data)
+			return err
+		},
+		end: func(w io.Writer) error {
+			_, err := io.WriteString(w, `],"success":true}`)
+			return err
+		},
+		fail: func(w io.Writer, failure []byte) error {
+			// failure is {"success":false,...}, merge it in the envelope
+			_, err := fmt.Fprintf(w, `],%s`, failure[1:])
+			return err
+		},
+	})
+}
+
+// StreamSSE writes each item as a server-sent event with its index as id,
+// followed by a "done" event, or an "error" event on failure
+func StreamSSE[T any](c *gin.Context, next Next[T]) {
+	stream(c, next, streamFormat{
+		contentType: MediaTypeEventStream,
+		flushEach:   true,
+		item: func(w io.Writer, index int, data []byte) error {
+			_, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", index, data)
+			return err
+		},
+		end: func(w io.Writer) error {
+			_, err := io.WriteString(w, "event: done\ndata: {}\n\n")
+			return err
+		},
+		fail: func(w io.Writer, failure []byte) error {
+			_, err := fmt.Fprintf(w, "event: error\ndata: %s\n\n", failure)
+			return err
+		},
+	})
+}
+
+// streamFormat frames the items of a stream
+type streamFormat struct {
+	contentType string
+	flushEach   bool
+	begin       func(w io.Writer) error
+	item        func(w io.Writer, index int, data []byte) error
+	end         func(w io.Writer) error
+	// fail writes the error envelope of a failure after the headers were sent
+	fail func(w io.Writer, failure []byte) error
+}
+
+// stream writes the items as they are produced. Errors before the first
+// item are sent as a regular error response; it stops when the client
+// goes away.
+func stream[T any](c *gin.Context, next Next[T], format streamFormat) {
+	ctx := c.Request.Context()
+	log := requestLogger(ctx)
+
+	item, err := next()
+	if err != nil && err != io.EOF {
+		Error(c, err)
+		return
+	}
+
+	c.Header("Content-Type", format.contentType)
+	c.Header("Cache-Control", "no-cache")
+	// Disable proxy buffering, e.g. nginx
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := &deadlineWriter{
+		writer:     c.Writer,
+		controller: http.NewResponseController(c.Writer),
+		timeout:    currentOptions().StreamWriteTimeout,
+	}
+
+	if format.begin != nil {
+		if err := format.begin(w); err != nil {
+			return
+		}
+	}
+
+	count := 0
+	lastFlush := time.Now()
+	pending := false
+	flush := func() {
+		c.Writer.Flush()
+		lastFlush = time.Now()
+		pending = false
+	}
+
+	// fetch waits for the next item, flushing the pending items once they
+	// waited streamFlushInterval so a slow source does not hold them back
+	fetch := func() (T, error) {
+		if !pending {
+			return next()
+		}
+		wait := streamFlushInterval - time.Since(lastFlush)
+		if wait <= 0 {
+			flush()
+			return next()
+		}
+
+		done := make(chan streamResult[T], 1)
+		go func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					done <- streamResult[T]{panicked: rec}
+				}
+			}()
+			item, err := next()
+			done <- streamResult[T]{item: item, err: err}
+		}()
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		var result streamResult[T]
+		select {
+		case result = <-done:
+		case <-timer.C:
+			flush()
+			result = <-done
+		}
+		if result.panicked != nil {
+			panic(result.panicked)
+		}
+		return result.item, result.err
+	}
+
+	for ; err == nil; item, err = fetch() {
+		if ctx.Err() != nil {
+			log.Debug("Stream closed by client", "items", count)
+			return
+		}
+
+		data, marshalErr := json.Marshal(item)
+		if marshalErr != nil {
+			err = marshalErr
+			break
+		}
+		if writeErr := format.item(w, count, data); writeErr != nil {
+			log.Debug("Stream write failed", "items", count, "error", writeErr)
+			return
+		}
+		count++
+		pending = true
+
+		if format.flushEach || time.Since(lastFlush) >= streamFlushInterval {
+			flush()
+		}
+	}
+
+	if err == io.EOF {
+		if format.end != nil {
+			_ = format.end(w)
+		}
+		c.Writer.Flush()
+		return
+	}
+
+	if ctx.Err() != nil {
+		log.Debug("Stream closed by client", "items", count)
+		return
+	}
+
+	statusCode, detail := resolveError(c, err)
+	failure, _ := json.Marshal(Response{
+		Success: false,
+		Error:   detail,
+		Meta: ErrorMeta{
+			RequestID: requestid.FromContext(ctx),
+		},
+	})
+	log.Warn("Stream failed after headers were sent", "items", count, "status", statusCode)
+
+	_ = format.fail(w, failure)
+	c.Writer.Flush()
+}
+
+// streamResult is the outcome of a call to Next made while items are
+// pending, a panic is raised again by the stream goroutine
+type streamResult[T any] struct {
+	item     T
+	err      error
+	panicked interface{}
+}
+
+// deadlineWriter pushes the write deadline before every write, so long
+// streams are not cut by the server write timeout while stalled clients
+// still are
+type deadlineWriter struct {
+	writer     io.Writer
+	controller *http.ResponseController
+	timeout    time.Duration
+}
+
+func (w *deadlineWriter) Write(data []byte) (int, error) {
+	if w.timeout > 0 {
+		// Not every writer supports deadlines, e.g. in tests
+		_ = w.controller.SetWriteDeadline(time.Now().Add(w.timeout))
+	}
+	return w.writer.Write(data)
+}
@@ -0,0 +1,182 @@
+package response
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang-template/pkg/common/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type streamItem struct {
+	ID int `json:"id"`
+}
+
+// items returns the items, then fails with err or ends with io.EOF
+func items(err error, ids ...int) Next[streamItem] {
+	i := 0
+	return func() (streamItem, error) {
+		if i < len(ids) {
+			i++
+			return streamItem{ID: ids[i-1]}, nil
+		}
+		if err != nil {
+			return streamItem{}, err
+		}
+		return streamItem{}, io.EOF
+	}
+}
+
+func serveStream(accept string, handler func(c *gin.Context)) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/items", nil)
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	handler(c)
+	return rec
+}
+
+func TestStreamFraming(t *testing.T) {
+	failed := errors.FromCode(errors.CodeServiceUnavailable, nil)
+	const failure = `{"success":false,"error":{"code":"SERVICE_UNAVAILABLE","message":"Service temporarily unavailable"},"meta":{}}`
+
+	tests := []struct {
+		name            string
+		stream          func(c *gin.Context, next Next[streamItem])
+		next            Next[streamItem]
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "ndjson",
+			stream:          StreamNDJSON[streamItem],
+			next:            items(nil, 1, 2),
+			wantContentType: MediaTypeNDJSON,
+			wantBody:        "{\"id\":1}\n{\"id\":2}\n",
+		},
+		{
+			name:            "ndjson error trailer",
+			stream:          StreamNDJSON[streamItem],
+			next:            items(failed, 1),
+			wantContentType: MediaTypeNDJSON,
+			wantBody:        "{\"id\":1}\n" + failure + "\n",
+		},
+		{
+			name:            "json array",
+			stream:          StreamJSONArray[streamItem],
+			next:            items(nil, 1, 2),
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"data":[{"id":1},{"id":2}],"success":true}`,
+		},
+		{
+			name:            "empty json array",
+			stream:          StreamJSONArray[streamItem],
+			next:            items(nil),
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"data":[],"success":true}`,
+		},
+		{
+			name:            "json array error trailer",
+			stream:          StreamJSONArray[streamItem],
+			next:            items(failed, 1),
+			wantContentType: "application/json; charset=utf-8",
+			wantBody:        `{"data":[{"id":1}],` + failure[1:],
+		},
+		{
+			name:            "sse",
+			stream:          StreamSSE[streamItem],
+			next:            items(nil, 1, 2),
+			wantContentType: MediaTypeEventStream,
+			wantBody:        "id: 0\ndata: {\"id\":1}\n\nid: 1\ndata: {\"id\":2}\n\nevent: done\ndata: {}\n\n",
+		},
+		{
+			name:            "sse error trailer",
+			stream:          StreamSSE[streamItem],
+			next:            items(failed, 1),
+			wantContentType: MediaTypeEventStream,
+			wantBody:        "id: 0\ndata: {\"id\":1}\n\nevent: error\ndata: " + failure + "\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveStream("", func(c *gin.Context) { tt.stream(c, tt.next) })
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestStreamErrorBeforeFirstItem(t *testing.T) {
+	rec := serveStream("", func(c *gin.Context) {
+		StreamNDJSON(c, items(errors.FromCode(errors.CodeServiceUnavailable, nil)))
+	})
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got == MediaTypeNDJSON {
+		t.Error("error response was framed as NDJSON")
+	}
+}
+
+func TestStreamNegotiation(t *testing.T) {
+	tests := []struct {
+		accept     string
+		wantStatus int
+		wantType   string
+	}{
+		{accept: "", wantStatus: http.StatusOK, wantType: "application/json; charset=utf-8"},
+		{accept: "application/x-ndjson", wantStatus: http.StatusOK, wantType: MediaTypeNDJSON},
+		{accept: "text/event-stream", wantStatus: http.StatusOK, wantType: MediaTypeEventStream},
+		{accept: "application/json;q=0, */*", wantStatus: http.StatusOK, wantType: MediaTypeNDJSON},
+		{accept: "text/csv", wantStatus: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			rec := serveStream(tt.accept, func(c *gin.Context) { Stream(c, items(nil, 1)) })
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantType != "" && rec.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), tt.wantType)
+			}
+		})
+	}
+}
+
+func TestFromChannel(t *testing.T) {
+	ch := make(chan int, 2)
+	ch <- 1
+	close(ch)
+
+	next := FromChannel(context.Background(), ch)
+	if item, err := next(); item != 1 || err != nil {
+		t.Fatalf("next() = %d, %v, want 1", item, err)
+	}
+	if _, err := next(); err != io.EOF {
+		t.Fatalf("next() error = %v, want io.EOF", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := FromChannel(ctx, make(chan int))(); err != context.Canceled {
+		t.Fatalf("next() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,61 @@
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"golang-template/pkg/common/requestid"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Options configures an outgoing HTTP client
+type Options struct {
+	// Timeout for the whole request, zero means DefaultTimeout
+	Timeout time.Duration
+	// Transport to wrap, nil means http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// DefaultTimeout is used when Options.Timeout is not set
+const DefaultTimeout = 30 * time.Second
+
+// New creates an HTTP client that forwards the request ID and the trace
+// context found in the request context so downstream logs and spans can be
+// correlated. Always build outgoing requests with http.NewRequestWithContext.
+func New(opts Options) *http.Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: NewTransport(opts.Transport),
+	}
+}
+
+// NewTransport wraps base so every request carries the X-Request-ID and
+// traceparent headers and is recorded as a client span
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(&requestIDTransport{base: base})
+}
+
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := requestid.FromContext(req.Context())
+	if id == "" || req.Header.Get(requestid.Header) != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	clone := req.Clone(req.Context())
+	clone.Header.Set(requestid.Header, id)
+
+	return t.base.RoundTrip(clone)
+}
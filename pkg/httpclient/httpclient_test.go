@@ -0,0 +1,56 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang-template/pkg/common/requestid"
+)
+
+func TestTransportForwardsRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctxID  string
+		header string
+		want   string
+	}{
+		{name: "from context", ctxID: "req-1", want: "req-1"},
+		{name: "explicit header wins", ctxID: "req-1", header: "other", want: "other"},
+		{name: "none", want: ""},
+	}
+
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(requestid.Header)
+	}))
+	defer server.Close()
+
+	client := New(Options{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ctxID != "" {
+				ctx = requestid.NewContext(ctx, tt.ctxID)
+			}
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			if tt.header != "" {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if got != tt.want {
+				t.Errorf("%s = %q, want %q", requestid.Header, got, tt.want)
+			}
+			if tt.header == "" && req.Header.Get(requestid.Header) != "" {
+				t.Error("the caller's request was modified")
+			}
+		})
+	}
+}
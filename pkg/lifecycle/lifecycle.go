@@ -0,0 +1,327 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang-template/infrastructure/logger"
+)
+
+// Hook is a named unit of startup and shutdown work
+type Hook struct {
+	// Name identifies the hook in logs and DependsOn references
+	Name string
+	// DependsOn lists hooks that must start before this one and stop after it
+	DependsOn []string
+	// OnStart runs during Start in dependency order
+	OnStart func(ctx context.Context) error
+	// OnStop runs during Shutdown in reverse start order
+	OnStop func(ctx context.Context) error
+	// Timeout overrides Options.HookTimeout for OnStop, OnStart runs with
+	// the context passed to Start
+	Timeout time.Duration
+}
+
+// Options configures the shutdown sequence
+type Options struct {
+	// PreStopDelay is waited after readiness flips to failing so load
+	// balancers stop routing new traffic before the server drains
+	PreStopDelay time.Duration
+	// HookTimeout is the default deadline for each stop hook
+	HookTimeout time.Duration
+}
+
+// Manager runs startup hooks in dependency order, tracks background
+// goroutines and drives the graceful shutdown sequence
+type Manager struct {
+	log     logger.Logger
+	options Options
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started []Hook
+
+	ready atomic.Bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	abort     chan error
+	abortOnce sync.Once
+	handoff   atomic.Bool
+}
+
+const defaultHookTimeout = 5 * time.Second
+
+// New creates a lifecycle manager
+func New(log logger.Logger, options Options) *Manager {
+	if options.HookTimeout <= 0 {
+		options.HookTimeout = defaultHookTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Manager{
+		log:     log,
+		options: options,
+		ctx:     ctx,
+		cancel:  cancel,
+		abort:   make(chan error, 1),
+	}
+}
+
+// Append registers a hook. Hooks must be appended before Start.
+func (m *Manager) Append(hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks = append(m.hooks, hook)
+}
+
+// Start runs every OnStart hook in dependency order and marks the manager
+// ready. If a hook fails, the hooks already started are stopped.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	ordered, err := sortHooks(m.hooks)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range ordered {
+		if hook.OnStart != nil {
+			m.log.Debug("Starting", "hook", hook.Name)
+
+			if err := hook.OnStart(ctx); err != nil {
+				m.stopHooks(ctx)
+				return fmt.Errorf("lifecycle: start %s: %w", hook.Name, err)
+			}
+		}
+
+		m.mu.Lock()
+		m.started = append(m.started, hook)
+		m.mu.Unlock()
+	}
+
+	m.ready.Store(true)
+	return nil
+}
+
+// Ready reports whether the application should receive traffic
+func (m *Manager) Ready() bool {
+	return m.ready.Load()
+}
+
+// Go runs fn in a tracked background goroutine. The context is cancelled
+// when shutdown begins and Shutdown waits for fn to return.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() {
+			if rec := recover(); rec != nil {
+				m.log.Error("Background goroutine panicked", "name", name, "panic", rec)
+			}
+		}()
+
+		fn(m.ctx)
+	}()
+}
+
+// Abort requests a shutdown because of an unrecoverable error
+func (m *Manager) Abort(err error) {
+	m.abortOnce.Do(func() {
+		m.abort <- err
+	})
+}
+
+// Handoff ends Wait because another process took over the listeners. The
+// pre-stop delay is skipped since traffic keeps flowing to the new process.
+func (m *Manager) Handoff() {
+	m.handoff.Store(true)
+	m.Abort(nil)
+}
+
+// Wait blocks until SIGINT or SIGTERM is received or Abort is called.
+// It returns the error passed to Abort, if any.
+func (m *Manager) Wait() error {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	select {
+	case sig := <-quit:
+		m.log.Info("Received shutdown signal", "signal", sig.String())
+		return nil
+	case err := <-m.abort:
+		return err
+	}
+}
+
+// Shutdown flips readiness to failing, waits the pre-stop delay, stops the
+// background goroutines and then runs the OnStop hooks in reverse start
+// order, each bounded by its own deadline
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.ready.Store(false)
+
+	if m.options.PreStopDelay > 0 && !m.handoff.Load() {
+		m.log.Info("Waiting before draining", "delay", m.options.PreStopDelay)
+		select {
+		case <-time.After(m.options.PreStopDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	var errs []error
+
+	m.cancel()
+	if err := m.waitBackground(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := m.stopHooks(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// waitBackground waits for the goroutines started with Go
+func (m *Manager) waitBackground(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, m.options.HookTimeout)
+	defer cancel()
+
+	select {
+	case <-done:
+		return nil
+	case <-waitCtx.Done():
+		return fmt.Errorf("lifecycle: background goroutines: %w", waitCtx.Err())
+	}
+}
+
+// stopHooks runs the OnStop hooks of started hooks in reverse order
+func (m *Manager) stopHooks(ctx context.Context) error {
+	m.mu.Lock()
+	started := m.started
+	m.started = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		hook := started[i]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		m.log.Debug("Stopping", "hook", hook.Name)
+
+		hookCtx, cancel := context.WithTimeout(ctx, m.hookTimeout(hook))
+		err := m.runStop(hookCtx, hook)
+		cancel()
+
+		if err != nil {
+			m.log.Error("Shutdown hook failed", "hook", hook.Name, "error", err)
+			errs = append(errs, fmt.Errorf("lifecycle: stop %s: %w", hook.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Manager) hookTimeout(hook Hook) time.Duration {
+	if hook.Timeout > 0 {
+		return hook.Timeout
+	}
+	return m.options.HookTimeout
+}
+
+// runStop returns when OnStop returns or the context expires, so a hook
+// ignoring its context cannot block the remaining hooks. A hook still
+// running is abandoned and logged again if it returns before the process
+// exits.
+func (m *Manager) runStop(ctx context.Context, hook Hook) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- hook.OnStop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		m.log.Warn("Shutdown hook abandoned after its deadline", "hook", hook.Name, "timeout", m.hookTimeout(hook))
+		go func() {
+			err := <-done
+			m.log.Warn("Abandoned shutdown hook returned", "hook", hook.Name, "error", err)
+		}()
+		return ctx.Err()
+	}
+}
+
+// sortHooks orders hooks so that every hook comes after its dependencies,
+// keeping registration order between independent hooks
+func sortHooks(hooks []Hook) ([]Hook, error) {
+	byName := make(map[string]Hook, len(hooks))
+	for _, hook := range hooks {
+		if _, exists := byName[hook.Name]; exists {
+			return nil, fmt.Errorf("lifecycle: duplicate hook %q", hook.Name)
+		}
+		byName[hook.Name] = hook
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(hooks))
+	ordered := make([]Hook, 0, len(hooks))
+
+	var visit func(hook Hook) error
+	visit = func(hook Hook) error {
+		switch state[hook.Name] {
+		case visiting:
+			return fmt.Errorf("lifecycle: dependency cycle at hook %q", hook.Name)
+		case visited:
+			return nil
+		}
+
+		state[hook.Name] = visiting
+		for _, dep := range hook.DependsOn {
+			depHook, exists := byName[dep]
+			if !exists {
+				return fmt.Errorf("lifecycle: hook %q depends on unknown hook %q", hook.Name, dep)
+			}
+			if err := visit(depHook); err != nil {
+				return err
+			}
+		}
+		state[hook.Name] = visited
+
+		ordered = append(ordered, hook)
+		return nil
+	}
+
+	for _, hook := range hooks {
+		if err := visit(hook); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,202 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang-template/infrastructure/logger"
+)
+
+func hookNames(hooks []Hook) string {
+	names := make([]string, len(hooks))
+	for i, hook := range hooks {
+		names[i] = hook.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSortHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   []Hook
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "registration order",
+			hooks: []Hook{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			want:  "a,b,c",
+		},
+		{
+			name: "dependencies first",
+			hooks: []Hook{
+				{Name: "http", DependsOn: []string{"modules"}},
+				{Name: "modules", DependsOn: []string{"firebase"}},
+				{Name: "firebase"},
+				{Name: "metrics"},
+			},
+			want: "firebase,modules,http,metrics",
+		},
+		{
+			name:    "duplicate",
+			hooks:   []Hook{{Name: "a"}, {Name: "a"}},
+			wantErr: `duplicate hook "a"`,
+		},
+		{
+			name:    "unknown dependency",
+			hooks:   []Hook{{Name: "a", DependsOn: []string{"b"}}},
+			wantErr: `depends on unknown hook "b"`,
+		},
+		{
+			name:    "cycle",
+			hooks:   []Hook{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"a"}}},
+			wantErr: "dependency cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sortHooks(tt.hooks)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("sortHooks() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sortHooks() error = %v", err)
+			}
+			if names := hookNames(got); names != tt.want {
+				t.Errorf("sortHooks() = %s, want %s", names, tt.want)
+			}
+		})
+	}
+}
+
+// recorder records the order hooks run in
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) hook(name string, deps ...string) Hook {
+	return Hook{
+		Name:      name,
+		DependsOn: deps,
+		OnStart:   func(context.Context) error { r.add("start " + name); return nil },
+		OnStop:    func(context.Context) error { r.add("stop " + name); return nil },
+	}
+}
+
+func (r *recorder) add(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.calls, ",")
+}
+
+func TestManagerStartAndShutdown(t *testing.T) {
+	rec := &recorder{}
+	m := New(logger.Default(), Options{})
+	m.Append(rec.hook("http", "db"))
+	m.Append(rec.hook("db"))
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !m.Ready() {
+		t.Error("Ready() = false after Start")
+	}
+
+	var stopped atomic.Bool
+	m.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		stopped.Store(true)
+	})
+
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if m.Ready() {
+		t.Error("Ready() = true after Shutdown")
+	}
+	if !stopped.Load() {
+		t.Error("background goroutine still running after Shutdown")
+	}
+	if got, want := rec.String(), "start db,start http,stop http,stop db"; got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}
+
+func TestManagerStartFailureStopsStartedHooks(t *testing.T) {
+	rec := &recorder{}
+	m := New(logger.Default(), Options{})
+	m.Append(rec.hook("db"))
+	m.Append(Hook{Name: "http", OnStart: func(context.Context) error { return errors.New("bind failed") }})
+
+	err := m.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "start http: bind failed") {
+		t.Fatalf("Start() error = %v, want the http failure", err)
+	}
+	if got, want := rec.String(), "start db,stop db"; got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}
+
+func TestManagerStartIgnoresHookTimeout(t *testing.T) {
+	m := New(logger.Default(), Options{HookTimeout: 10 * time.Millisecond})
+	m.Append(Hook{Name: "slow", Timeout: 10 * time.Millisecond, OnStart: func(ctx context.Context) error {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v, want the start hook to run past Timeout", err)
+	}
+}
+
+func TestManagerAbandonsStuckStopHook(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	rec := &recorder{}
+	m := New(logger.Default(), Options{HookTimeout: time.Second})
+	m.Append(rec.hook("db"))
+	m.Append(Hook{
+		Name:      "stuck",
+		DependsOn: []string{"db"},
+		Timeout:   20 * time.Millisecond,
+		// Ignores its context
+		OnStop: func(context.Context) error { <-release; return nil },
+	})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	start := time.Now()
+	err := m.Shutdown(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() error = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Shutdown() took %v, want the stuck hook abandoned after its timeout", elapsed)
+	}
+	if got, want := rec.String(), "start db,stop db"; got != want {
+		t.Errorf("calls = %s, want %s", got, want)
+	}
+}